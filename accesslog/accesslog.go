@@ -0,0 +1,322 @@
+/*
+Package accesslog feeds call outcomes read from structured access logs
+into circuit breakers.
+
+Each line of the log is expected to be a JSON object describing a single
+request, containing at least an HTTP status code. The outcome of each
+request is imported into the breaker for its upstream using
+Breaker.ImportOutcomes, so a breaker can guard traffic on code paths that
+are not yet instrumented with Protect.
+
+	t := &accesslog.Tailer{
+		Breaker: func(upstream string) *breaker.Breaker {
+			return breakers[upstream]
+		},
+	}
+	err := t.Follow(ctx, "/var/log/proxy/access.log")
+*/
+package accesslog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/billglover/breaker"
+)
+
+// Default field names used when the corresponding Tailer field is empty.
+const (
+	DefaultUpstreamField = "upstream"
+	DefaultStatusField   = "status"
+	DefaultLatencyField  = "latency"
+)
+
+// DefaultMaxLineSize is the length of the longest line read when
+// Tailer.MaxLineSize is zero.
+const DefaultMaxLineSize = 1 << 20
+
+// errLineTooLong is returned by readLine for a line longer than the
+// maximum line size
+var errLineTooLong = errors.New("accesslog: line too long")
+
+// Tailer reads JSON access log lines and imports their outcomes into
+// breakers.
+type Tailer struct {
+	// Breaker returns the breaker for the named upstream, or nil if
+	// entries for the upstream should be ignored. It must be set.
+	Breaker func(upstream string) *breaker.Breaker
+
+	// UpstreamField, StatusField and LatencyField name the fields of each
+	// entry holding the upstream name, the status code and the request
+	// latency. If empty, the package defaults are used.
+	UpstreamField string
+	StatusField   string
+	LatencyField  string
+
+	// LatencyUnit is the unit of numeric latency values. If zero,
+	// latencies are read as milliseconds. String latency values are
+	// parsed with time.ParseDuration.
+	LatencyUnit time.Duration
+
+	// Failure reports whether a status code represents a failure. If nil,
+	// status codes of 500 and above are failures.
+	Failure func(status int) bool
+
+	// PollInterval is the time Follow waits for new lines after reaching
+	// the end of the file. If zero, it waits for one second.
+	PollInterval time.Duration
+
+	// MaxLineSize is the length in bytes of the longest line read. Longer
+	// lines are skipped. If zero, lines of up to DefaultMaxLineSize bytes
+	// are read.
+	MaxLineSize int
+}
+
+// Run reads entries from r until it reaches the end of the input or ctx
+// is done. Lines that cannot be parsed or are longer than MaxLineSize
+// are skipped. It returns the first error encountered reading from r, or
+// the context's error.
+func (t *Tailer) Run(ctx context.Context, r io.Reader) error {
+	max := t.MaxLineSize
+	if max == 0 {
+		max = DefaultMaxLineSize
+	}
+
+	br := bufio.NewReader(r)
+	for {
+		line, err := readLine(br, max)
+		switch {
+		case err == io.EOF:
+			return nil
+		case err == errLineTooLong:
+			continue
+		case err != nil:
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		upstream, o, err := t.parse(line)
+		if err != nil {
+			continue
+		}
+
+		if b := t.Breaker(upstream); b != nil {
+			b.ImportOutcomes([]breaker.Outcome{o})
+		}
+	}
+}
+
+// readLine returns the next line from r without its line ending. A line
+// longer than max bytes is read to its end and errLineTooLong returned in
+// its place. The last line of the input need not end in a newline.
+func readLine(r *bufio.Reader, max int) ([]byte, error) {
+	var line []byte
+	long := false
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if long == false {
+			line = append(line, chunk...)
+			if len(bytes.TrimRight(line, "\r\n")) > max {
+				line, long = nil, true
+			}
+		}
+
+		switch {
+		case err == bufio.ErrBufferFull:
+			continue
+		case err == io.EOF && (len(line) > 0 || long):
+		case err != nil:
+			return nil, err
+		}
+
+		if long {
+			return nil, errLineTooLong
+		}
+		return bytes.TrimRight(line, "\r\n"), nil
+	}
+}
+
+// Follow reads entries from the named file, waiting for new lines to be
+// appended once it reaches the end, until ctx is done. Reading starts at
+// the current end of the file so that historic entries are not replayed.
+// If the file is rotated or truncated, reading continues from the start
+// of the new or truncated file.
+func (t *Tailer) Follow(ctx context.Context, name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		f.Close()
+		return err
+	}
+
+	interval := t.PollInterval
+	if interval == 0 {
+		interval = time.Second
+	}
+
+	fl := &follower{ctx: ctx, name: name, f: f, interval: interval}
+	defer func() {
+		fl.f.Close()
+	}()
+
+	err = t.Run(ctx, fl)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// parse reads a single log entry
+func (t *Tailer) parse(line []byte) (string, breaker.Outcome, error) {
+	var entry map[string]any
+	if err := json.Unmarshal(line, &entry); err != nil {
+		return "", breaker.Outcome{}, err
+	}
+
+	status, err := number(entry[field(t.StatusField, DefaultStatusField)])
+	if err != nil {
+		return "", breaker.Outcome{}, fmt.Errorf("reading status: %w", err)
+	}
+
+	var o breaker.Outcome
+	if t.failure(int(status)) {
+		o.Err = fmt.Errorf("status %d", int(status))
+	}
+
+	if v, ok := entry[field(t.LatencyField, DefaultLatencyField)]; ok {
+		o.Latency = t.latency(v)
+	}
+
+	upstream, _ := entry[field(t.UpstreamField, DefaultUpstreamField)].(string)
+	return upstream, o, nil
+}
+
+// failure reports whether a status code represents a failure
+func (t *Tailer) failure(status int) bool {
+	if t.Failure != nil {
+		return t.Failure(status)
+	}
+	return status >= 500
+}
+
+// latency converts a latency value to a duration, returning zero if the
+// value cannot be read
+func (t *Tailer) latency(v any) time.Duration {
+	if s, ok := v.(string); ok {
+		if d, err := time.ParseDuration(s); err == nil {
+			return d
+		}
+	}
+
+	n, err := number(v)
+	if err != nil {
+		return 0
+	}
+
+	unit := t.LatencyUnit
+	if unit == 0 {
+		unit = time.Millisecond
+	}
+	return time.Duration(n * float64(unit))
+}
+
+// field returns name, or def if name is empty
+func field(name, def string) string {
+	if name == "" {
+		return def
+	}
+	return name
+}
+
+// number reads a JSON number, accepting numbers encoded as strings
+func number(v any) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case string:
+		return strconv.ParseFloat(n, 64)
+	default:
+		return 0, fmt.Errorf("unexpected value %v", v)
+	}
+}
+
+// follower is a reader that waits for more data at the end of a named
+// file, following it across rotation and truncation
+type follower struct {
+	ctx      context.Context
+	name     string
+	f        *os.File
+	interval time.Duration
+}
+
+func (f *follower) Read(p []byte) (int, error) {
+	for {
+		n, err := f.f.Read(p)
+		if n > 0 || err != io.EOF {
+			return n, err
+		}
+
+		reopened, err := f.reopen()
+		if err != nil {
+			return 0, err
+		}
+		if reopened {
+			continue
+		}
+
+		select {
+		case <-f.ctx.Done():
+			return 0, io.EOF
+		case <-time.After(f.interval):
+		}
+	}
+}
+
+// reopen moves to the start of the file if it has been truncated below
+// the current offset, or opens the file now at the name if it has been
+// rotated. It reports whether reading should resume immediately. A
+// missing file is assumed to be mid-rotation and is checked again later.
+func (f *follower) reopen() (bool, error) {
+	info, err := os.Stat(f.name)
+	if err != nil {
+		return false, nil
+	}
+
+	current, err := f.f.Stat()
+	if err != nil {
+		return false, err
+	}
+
+	if os.SameFile(info, current) == false {
+		nf, err := os.Open(f.name)
+		if err != nil {
+			return false, nil
+		}
+		f.f.Close()
+		f.f = nf
+		return true, nil
+	}
+
+	offset, err := f.f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return false, err
+	}
+	if info.Size() < offset {
+		_, err := f.f.Seek(0, io.SeekStart)
+		return err == nil, err
+	}
+	return false, nil
+}
@@ -0,0 +1,191 @@
+package accesslog
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/billglover/breaker"
+)
+
+const log = `{"upstream": "payments", "status": 200, "latency": 12}
+{"upstream": "payments", "status": 503, "latency": "250ms"}
+not json
+{"upstream": "payments", "status": "502"}
+{"upstream": "search", "status": 500}
+{"upstream": "unknown", "status": 500}
+`
+
+func TestRun(t *testing.T) {
+	payments := breaker.NewBreaker().TripAfter(2)
+	search := breaker.NewBreaker()
+
+	tl := &Tailer{
+		Breaker: func(upstream string) *breaker.Breaker {
+			return map[string]*breaker.Breaker{"payments": payments, "search": search}[upstream]
+		},
+	}
+
+	err := tl.Run(context.Background(), strings.NewReader(log))
+	if err != nil {
+		t.Fatalf("unexpected response: %v", err)
+	}
+
+	if payments.CurrentState() != breaker.StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", breaker.StateOpen, payments.CurrentState())
+	}
+
+	if payments.SuccessCount() != 1 {
+		t.Fatalf("unexpected success count: want %d, got %d", 1, payments.SuccessCount())
+	}
+
+	if search.FailCount() != 1 {
+		t.Fatalf("unexpected fail count: want %d, got %d", 1, search.FailCount())
+	}
+}
+
+func TestRunFields(t *testing.T) {
+	cb := breaker.NewBreaker()
+
+	tl := &Tailer{
+		Breaker:       func(string) *breaker.Breaker { return cb },
+		StatusField:   "code",
+		LatencyField:  "request_time",
+		LatencyUnit:   time.Second,
+		Failure:       func(status int) bool { return status == 429 },
+		UpstreamField: "host",
+	}
+
+	err := tl.Run(context.Background(), strings.NewReader(`{"host": "api", "code": 429, "request_time": 0.5}`))
+	if err != nil {
+		t.Fatalf("unexpected response: %v", err)
+	}
+
+	if cb.FailCount() != 1 {
+		t.Fatalf("unexpected fail count: want %d, got %d", 1, cb.FailCount())
+	}
+
+	if cb.Latency() != 500*time.Millisecond {
+		t.Fatalf("unexpected latency: want %v, got %v", 500*time.Millisecond, cb.Latency())
+	}
+}
+
+func TestFollow(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "access.log")
+	if err := os.WriteFile(name, []byte(`{"status": 500}`+"\n"), 0o644); err != nil {
+		t.Fatalf("unexpected error writing log: %v", err)
+	}
+
+	cb := breaker.NewBreaker()
+	tl := &Tailer{
+		Breaker:      func(string) *breaker.Breaker { return cb },
+		PollInterval: time.Millisecond,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- tl.Follow(ctx, name)
+	}()
+
+	// give Follow time to open the file and reach the end
+	time.Sleep(20 * time.Millisecond)
+
+	f, err := os.OpenFile(name, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("unexpected error opening log: %v", err)
+	}
+	f.WriteString(`{"status": 200}` + "\n")
+	f.Close()
+
+	for cb.SuccessCount() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("unexpected error: want %v, got %v", context.Canceled, err)
+	}
+
+	// the existing entry is not replayed
+	if cb.FailCount() != 0 {
+		t.Fatalf("unexpected fail count: want %d, got %d", 0, cb.FailCount())
+	}
+}
+
+func TestRunLongLines(t *testing.T) {
+	cb := breaker.NewBreaker()
+	tl := &Tailer{
+		Breaker: func(string) *breaker.Breaker { return cb },
+	}
+
+	// lines longer than the scanner's default buffer are read
+	padding := strings.Repeat("x", 100*1024)
+	input := `{"status": 200, "padding": "` + padding + `"}` + "\n" + `{"status": 500}`
+	if err := tl.Run(context.Background(), strings.NewReader(input)); err != nil {
+		t.Fatalf("unexpected response: %v", err)
+	}
+	if cb.SuccessCount() != 1 || cb.FailCount() != 1 {
+		t.Fatalf("unexpected counts: want %d and %d, got %d and %d", 1, 1, cb.SuccessCount(), cb.FailCount())
+	}
+
+	// lines longer than the limit are skipped
+	cb = breaker.NewBreaker()
+	tl.MaxLineSize = 1024
+	if err := tl.Run(context.Background(), strings.NewReader(input)); err != nil {
+		t.Fatalf("unexpected response: %v", err)
+	}
+	if cb.SuccessCount() != 0 || cb.FailCount() != 1 {
+		t.Fatalf("unexpected counts: want %d and %d, got %d and %d", 0, 1, cb.SuccessCount(), cb.FailCount())
+	}
+}
+
+func TestFollowRotation(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "access.log")
+	if err := os.WriteFile(name, []byte(`{"status": 200}`+"\n"), 0o644); err != nil {
+		t.Fatalf("unexpected error writing log: %v", err)
+	}
+
+	cb := breaker.NewBreaker().TripAfter(10)
+	tl := &Tailer{
+		Breaker:      func(string) *breaker.Breaker { return cb },
+		PollInterval: time.Millisecond,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- tl.Follow(ctx, name)
+	}()
+
+	// give Follow time to open the file and reach the end
+	time.Sleep(20 * time.Millisecond)
+
+	// a rotated file is followed from its start
+	if err := os.Rename(name, filepath.Join(dir, "access.log.1")); err != nil {
+		t.Fatalf("unexpected error rotating log: %v", err)
+	}
+	if err := os.WriteFile(name, []byte(`{"status": 500}`+"\n"), 0o644); err != nil {
+		t.Fatalf("unexpected error writing log: %v", err)
+	}
+	for cb.FailCount() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// a file truncated below the offset read is followed from its start
+	if err := os.WriteFile(name, []byte(`{"status":200}`+"\n"), 0o644); err != nil {
+		t.Fatalf("unexpected error truncating log: %v", err)
+	}
+	for cb.SuccessCount() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("unexpected error: want %v, got %v", context.Canceled, err)
+	}
+}
@@ -0,0 +1,77 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+)
+
+// The functions in this file hold the logic shared by the transport
+// adapters in this repository, such as NewTransport, NewHandler and the
+// breakergrpc interceptors. They are exported so that integrations for
+// other transports can behave consistently.
+
+// errClassified records a call whose result was classified as a failure
+// even though it returned no error.
+var errClassified = errors.New("breaker: result classified as failure")
+
+// ProtectResult calls f protected by the breaker b and returns its
+// result. Unlike ProtectContext, the outcome is decided by isFailure,
+// which sees both the result and the error. This allows a response such
+// as an HTTP 503 to be counted as a failure while being returned to the
+// caller unchanged, and an error describing a bad request to be returned
+// while being counted as a success. If isFailure is nil, any error is a
+// failure. If f returns the error from Degraded, the call is counted as
+// degraded and a nil error is returned, as it is by Protect.
+//
+// If the call is rejected, the zero value of T is returned along with
+// the error from the breaker.
+func ProtectResult[T any](ctx context.Context, b *Breaker, f func(ctx context.Context) (T, error), isFailure func(v T, err error) bool) (T, error) {
+	if isFailure == nil {
+		isFailure = func(_ T, err error) bool { return err != nil }
+	}
+
+	var v T
+	var callErr error
+	err := b.ProtectContext(ctx, func(ctx context.Context) error {
+		v, callErr = f(ctx)
+		switch {
+		case isFailure(v, callErr) == false:
+			return nil
+		case callErr == nil:
+			return errClassified
+		default:
+			return callErr
+		}
+	})
+
+	// errors other than those from f come from the breaker itself
+	if err != nil && err != callErr && err != errClassified {
+		var zero T
+		return zero, err
+	}
+	if callErr == errDegraded {
+		return v, nil
+	}
+	return v, callErr
+}
+
+// RetryAfterHeader returns the value of a Retry-After header for a call
+// rejected by b, giving the number of seconds until the breaker is next
+// ready to let a call through. The value is rounded up and is at least
+// one second. An empty string is returned if the breaker is closed,
+// unless calls are being limited by ColdStart.
+func RetryAfterHeader(b *Breaker) string {
+	b.lock()
+	_, _, ramping := b.ramp(time.Now())
+	closed := b.state == StateClosed && ramping == false
+	d := b.retryAfter()
+	b.mu.Unlock()
+
+	if closed {
+		return ""
+	}
+	return strconv.Itoa(max(int(math.Ceil(d.Seconds())), 1))
+}
@@ -0,0 +1,95 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestProtectResult(t *testing.T) {
+	cb := NewBreaker().TripAfter(1)
+	errBadRequest := errors.New("bad request")
+
+	isFailure := func(status int, err error) bool {
+		return status >= 500
+	}
+
+	// an error that isn't classified as a failure is returned but not counted
+	status, err := ProtectResult(context.Background(), cb, func(ctx context.Context) (int, error) {
+		return 400, errBadRequest
+	}, isFailure)
+
+	if status != 400 || err != errBadRequest {
+		t.Fatalf("unexpected result: want %d, %v, got %d, %v", 400, errBadRequest, status, err)
+	}
+
+	if cb.SuccessCount() != 1 {
+		t.Fatalf("unexpected success count: want %d, got %d", 1, cb.SuccessCount())
+	}
+
+	// a result classified as a failure is returned and counted
+	status, err = ProtectResult(context.Background(), cb, func(ctx context.Context) (int, error) {
+		return 503, nil
+	}, isFailure)
+
+	if status != 503 || err != nil {
+		t.Fatalf("unexpected result: want %d, %v, got %d, %v", 503, nil, status, err)
+	}
+
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+
+	status, err = ProtectResult(context.Background(), cb, func(ctx context.Context) (int, error) {
+		return 200, nil
+	}, isFailure)
+
+	if status != 0 || !errors.Is(err, ErrOpen) {
+		t.Fatalf("unexpected result: want %d, %v, got %d, %v", 0, ErrOpen, status, err)
+	}
+}
+
+func TestProtectResultDefault(t *testing.T) {
+	cb := NewBreaker()
+
+	ProtectResult(context.Background(), cb, func(ctx context.Context) (string, error) {
+		return "", errors.New("error")
+	}, nil)
+
+	if cb.FailCount() != 1 {
+		t.Fatalf("unexpected fail count: want %d, got %d", 1, cb.FailCount())
+	}
+}
+
+func TestProtectResultDegraded(t *testing.T) {
+	cb := NewBreaker()
+
+	v, err := ProtectResult(context.Background(), cb, func(ctx context.Context) (int, error) {
+		return 7, Degraded()
+	}, nil)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v != 7 {
+		t.Fatalf("unexpected result: want %d, got %d", 7, v)
+	}
+	if cb.DegradedCount() != 1 || cb.FailCount() != 0 {
+		t.Fatalf("unexpected counts: want %d degraded and %d failed, got %d and %d", 1, 0, cb.DegradedCount(), cb.FailCount())
+	}
+}
+
+func TestRetryAfterHeader(t *testing.T) {
+	cb := NewBreaker().TripAfter(1).ResetAfter(2500 * time.Millisecond)
+
+	if ra := RetryAfterHeader(cb); ra != "" {
+		t.Fatalf("unexpected Retry-After: want %q, got %q", "", ra)
+	}
+
+	cb.Protect(errorFunc)
+
+	if ra := RetryAfterHeader(cb); ra != "3" {
+		t.Fatalf("unexpected Retry-After: want %q, got %q", "3", ra)
+	}
+}
@@ -0,0 +1,45 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// errFailed is the error reported to observers for a call that was
+// marked as failed through the function returned by Allow.
+var errFailed = errors.New("breaker: call reported as failed")
+
+// Allow asks the breaker for permission to make a call without wrapping
+// it in a function. It is intended for streaming or callback-based code
+// where the work can't easily be expressed as a closure. If the breaker
+// is open, ErrOpen is returned and no call should be made.
+//
+// Otherwise the caller performs the work itself and must call done
+// exactly once to report whether it succeeded. Subsequent calls to done
+// are ignored. Latency is measured from the call to Allow until done is
+// called. A disabled breaker always allows the call and records nothing.
+func (b *Breaker) Allow() (done func(success bool), err error) {
+	if b.isDisabled() {
+		return func(bool) {}, nil
+	}
+
+	a, err := b.admit(callOptions{}, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	var once sync.Once
+	done = func(success bool) {
+		once.Do(func() {
+			var err error
+			if success == false {
+				err = errFailed
+			}
+			b.complete(context.Background(), callOptions{}, a, time.Since(start), err)
+		})
+	}
+	return done, nil
+}
@@ -0,0 +1,96 @@
+package breaker
+
+import (
+	"errors"
+	"log"
+	"testing"
+	"time"
+)
+
+func TestAllow(t *testing.T) {
+	cb := NewBreaker()
+
+	done, err := cb.Allow()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	done(true)
+
+	if cb.SuccessCount() != 1 {
+		t.Fatalf("unexpected success count: want %d, got %d", 1, cb.SuccessCount())
+	}
+
+	done, err = cb.Allow()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	done(false)
+
+	// reporting the outcome again has no effect
+	done(false)
+
+	if cb.FailCount() != 1 {
+		t.Fatalf("unexpected fail count: want %d, got %d", 1, cb.FailCount())
+	}
+}
+
+func TestAllowTrips(t *testing.T) {
+	cb := NewBreaker().TripAfter(2)
+
+	for i := 0; i < 2; i++ {
+		done, err := cb.Allow()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		done(false)
+	}
+
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+
+	done, err := cb.Allow()
+	if !errors.Is(err, ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+	if done != nil {
+		t.Fatalf("unexpected done function: want nil")
+	}
+}
+
+func TestAllowProbe(t *testing.T) {
+	cb := NewBreaker().TripAfter(1).ResetAfter(10 * time.Millisecond).MaxProbes(1)
+	cb.Protect(errorFunc)
+
+	time.Sleep(20 * time.Millisecond)
+
+	done, err := cb.Allow()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// only one probe may be outstanding at a time
+	if _, err := cb.Allow(); !errors.Is(err, ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+
+	done(true)
+
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+}
+
+func ExampleBreaker_Allow() {
+	cb := NewBreaker()
+
+	done, err := cb.Allow()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	// perform the work you are trying to protect and report whether it
+	// succeeded
+	done(true)
+}
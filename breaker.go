@@ -5,32 +5,138 @@ By protecting calls to external systems with a circuit breaker, you are
 able to respond quickly in the event of system failure, avoiding the
 need to wait for a timeout.
 
-   cb := NewBreaker().TripAfter(5).ResetAfter(500)
+	cb := NewBreaker().TripAfter(5).ResetAfter(500)
 
 Further information on the circuit breaker pattern can be found in the
 Microsoft Azure Architecture Patterns documentation.
 
 https://docs.microsoft.com/en-us/azure/architecture/patterns/circuit-breaker
+
+The package depends only on the standard library. Integrations with
+gRPC, OpenTelemetry and Prometheus live in their own modules, in the
+breakergrpc, breakerotel and breakerprom directories, so that importing
+the breaker does not pull in their dependencies.
 */
 package breaker
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"iter"
+	"math/rand/v2"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// ErrOpen is returned when a call is rejected by an open breaker. Callers
+// can use errors.Is(err, ErrOpen) to distinguish a fast failure from an
+// error returned by the protected system.
+var ErrOpen = errors.New("breaker open")
+
+// errDegraded is returned by Degraded to mark a degraded result.
+var errDegraded = errors.New("breaker: degraded result")
+
+// Degraded returns a value that a protected function can return in
+// place of nil to indicate that it served its result in a degraded way,
+// for example from a cache or a fallback, rather than from the protected
+// system. Degraded transactions are counted by DegradedCount and are
+// neither successes nor failures, which keeps the success count an
+// accurate measure of genuine availability. Protect returns nil for
+// degraded transactions.
+func Degraded() error {
+	return errDegraded
+}
+
 // Breaker represents a circuit breaker. In normal use, an instance of
 // the circuit breaker should be used to protect a single external
 // system. Protecting multiple systems with a single instance of a
 // circuit breaker is not recommended.
+//
+// A Breaker is safe for concurrent use by multiple goroutines.
 type Breaker struct {
-	failCount    int
-	successCount int
-	lastFail     time.Time
-	state        State
-	shouldTrip   stateFunc
-	shouldReset  stateFunc
-	subscribers  []chan State
+	mu             sync.Mutex
+	name           string
+	failCount      int
+	successCount   int
+	lastFail       time.Time
+	changedAt      time.Time
+	enteredAt      time.Time
+	stateTime      [3]time.Duration
+	rejectedCount  int
+	failTimes      []time.Time
+	maxFailAge     time.Duration
+	state          State
+	shouldTrip     stateFunc
+	shouldReset    stateFunc
+	subscribers    []chan State
+	tripCh         chan struct{}
+	openedAt       time.Time
+	trips          []time.Time
+	flapLimit      int
+	flapWindow     time.Duration
+	flapDampen     time.Duration
+	lastAttempt    time.Time
+	anchor         Anchor
+	observers      []*observer
+	pending        []func(Observer)
+	obsTimeout     time.Duration
+	obsDropped     atomic.Int64
+	obsSkip        float64
+	tripAfter      int
+	tripRate       float64
+	tripPeriod     time.Duration
+	failRate       float64
+	minRequests    int
+	degradedCount  int
+	slowCount      int
+	slowCall       time.Duration
+	totalFail      int
+	totalSuccess   int
+	epoch          int
+	latency        time.Duration
+	latencies      []latencySample
+	latencyPos     int
+	latencyWindow  time.Duration
+	window         []bool
+	windowPos      int
+	windowLen      int
+	resetAfter     time.Duration
+	closeAfter     int
+	reopenAfter    int
+	maxProbes      int
+	backoffMax     time.Duration
+	retrips        int
+	jitter         float64
+	jitterSample   float64
+	recoverPanics  bool
+	probes         int
+	probeFails     int
+	probeSuccesses int
+	descriptions   map[State]string
+	guard          func(from, to State, reason Reason) bool
+	initialized    bool
+	forced         bool
+	illegal        int
+	disabled       bool
+	bucketCap      int
+	bucketLeak     time.Duration
+	bucketLevel    float64
+	bucketTime     time.Time
+	rampFrom       float64
+	rampTo         float64
+	rampDur        time.Duration
+	rampStart      time.Time
+	rampTime       time.Time
+	rampTokens     float64
+	burnFail       float64
+	burnReject     float64
+	burnWindow     time.Duration
+	burnSlots      []burnSlot
+	burning        [2]bool
 }
 
 // A StateFunc defines a function that can be used to determine a state
@@ -60,67 +166,332 @@ func (s State) String() string {
 	}
 }
 
+// MarshalText implements encoding.TextMarshaler so that states are
+// serialised by name, for example in JSON health and debug output.
+func (s State) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
 // NewBreaker returns an instance of a circuit breaker using the default
 // configuration.
 //
 // By default the circuit breaker will trip after 5 failed transactions,
 // enter the partially open state after 50ms. Once in the partially open
 // state it will reset if the next call is successful or trip if it fails.
+//
+// The zero value of Breaker is also ready to use, so a Breaker can be
+// embedded in another struct. The default configuration is applied the
+// first time it is used.
 func NewBreaker() *Breaker {
+	b := &Breaker{}
+	b.init()
+	return b
+}
+
+// init applies the default configuration to a breaker that has not yet
+// been used. It must be called with the lock held.
+func (b *Breaker) init() {
+	if b.initialized {
+		return
+	}
+	b.initialized = true
 
-	b := Breaker{}
 	b.state = StateClosed
-	b.TripAfter(5)
-	b.ResetAfter(50 * time.Millisecond)
-	return &b
+	b.enteredAt = time.Now()
+	b.setTripAfter(5)
+	b.setResetAfter(50 * time.Millisecond)
+	b.closeAfter = 1
+	b.reopenAfter = 1
+}
+
+// lock acquires the lock, initialising a zero value breaker first.
+func (b *Breaker) lock() {
+	b.mu.Lock()
+	b.init()
 }
 
 // FailCount returns the current count of failed transactions.
 func (b *Breaker) FailCount() int {
+	b.lock()
+	defer b.mu.Unlock()
 	return b.failCount
 }
 
 // SuccessCount returns the current count of successful transactions.
 func (b *Breaker) SuccessCount() int {
+	b.lock()
+	defer b.mu.Unlock()
 	return b.successCount
 }
 
+// DegradedCount returns the current count of transactions marked as
+// degraded by returning Degraded from the protected function.
+func (b *Breaker) DegradedCount() int {
+	b.lock()
+	defer b.mu.Unlock()
+	return b.degradedCount
+}
+
 // CurrentState returns the current state of the circuit breaker.
 func (b *Breaker) CurrentState() State {
+	b.lock()
+	defer b.mu.Unlock()
 	return b.state
 }
 
+// LastStateChange returns the time of the most recent state transition,
+// or the zero time if the breaker has never changed state.
+func (b *Breaker) LastStateChange() time.Time {
+	b.lock()
+	defer b.mu.Unlock()
+	return b.changedAt
+}
+
+// OpenSince returns the time at which the breaker last tripped and true
+// if it is currently open. If the breaker is closed or partially open,
+// the zero time and false are returned.
+func (b *Breaker) OpenSince() (time.Time, bool) {
+	b.lock()
+	defer b.mu.Unlock()
+	if b.state != StateOpen {
+		return time.Time{}, false
+	}
+	return b.openedAt, true
+}
+
 // fail increments the failCount
 func (b *Breaker) fail() {
+	b.record(false)
 	b.failCount++
+	b.totalFail++
 	b.lastFail = time.Now()
+	if b.maxFailAge > 0 {
+		b.failTimes = append(b.failTimes, b.lastFail)
+	}
+	if b.bucketCap > 0 {
+		b.drain()
+		b.bucketLevel++
+	}
+}
+
+// recentFailures returns the number of failures that count towards
+// tripping the breaker, discarding any older than the maximum failure
+// age.
+func (b *Breaker) recentFailures() int {
+	if b.maxFailAge <= 0 {
+		return b.failCount
+	}
+
+	cutoff := time.Now().Add(-b.maxFailAge)
+	i := 0
+	for i < len(b.failTimes) && b.failTimes[i].Before(cutoff) {
+		i++
+	}
+	b.failTimes = b.failTimes[i:]
+	return len(b.failTimes)
 }
 
 // success increments the successCount
 func (b *Breaker) success() {
+	b.record(true)
 	b.successCount++
+	b.totalSuccess++
 }
 
-// Reset returns the fail and success counters to zero
+// Reset closes the breaker and returns the fail and success counters to
+// zero. Subscribers are always notified, even if the breaker was
+// already closed. A breaker pinned by ForceOpen or ForceClose is
+// released.
 func (b *Breaker) Reset() {
-	b.state = StateClosed
-	b.failCount = 0
-	b.successCount = 0
-	b.notify(StateClosed)
+	b.lock()
+	defer b.unlock()
+	b.forced = false
+	b.resetCounters()
+	b.transition(StateClosed)
+}
+
+// ResetCounters returns the fail and success counters to zero without
+// changing the state of the breaker. Subscribers are not notified.
+func (b *Breaker) ResetCounters() {
+	b.lock()
+	defer b.mu.Unlock()
+	b.resetCounters()
+}
+
+// Close closes the breaker without changing the fail and success
+// counters. Subscribers are notified only if the breaker was not already
+// closed. A breaker pinned by ForceOpen or ForceClose is released.
+//
+// Since the counters that tripped the breaker are kept, the next failure
+// is likely to trip it again. Call ResetCounters as well, or use Reset,
+// to close the breaker with a fresh count.
+func (b *Breaker) Close() {
+	b.lock()
+	defer b.unlock()
+	b.forced = false
+	if b.state == StateClosed {
+		return
+	}
+	b.transition(StateClosed)
 }
 
-// partial returns the fail and success counters to zero
-func (b *Breaker) partial() {
-	b.state = StatePartial
+// Open trips the breaker without recording a failure. It can be used on
+// startup when an external signal indicates that the protected system is
+// already unavailable, so the breaker does not need to relearn a known
+// outage. The reset timeout is measured from the moment the breaker was
+// opened. Subscribers are notified only if the breaker was not already
+// open. A breaker pinned by ForceOpen or ForceClose is released.
+func (b *Breaker) Open() {
+	b.lock()
+	defer b.unlock()
+	b.forced = false
+	if b.state == StateOpen {
+		return
+	}
+	b.transition(StateOpen)
+}
+
+// resetCounters returns the fail and success counters to zero
+func (b *Breaker) resetCounters() {
 	b.failCount = 0
 	b.successCount = 0
-	b.notify(StatePartial)
+	b.degradedCount = 0
+	b.slowCount = 0
+	b.probeFails = 0
+	b.probeSuccesses = 0
+	b.bucketLevel = 0
+	b.failTimes = nil
+	b.windowPos = 0
+	b.windowLen = 0
+	b.epoch++
+}
+
+// transitions lists the states that can be reached from each state.
+var transitions = map[State][]State{
+	StateClosed:  {StateOpen, StateClosed},
+	StateOpen:    {StatePartial, StateClosed},
+	StatePartial: {StateOpen, StateClosed},
+}
+
+// transitionError is returned when an illegal state transition is
+// attempted.
+type transitionError struct {
+	from State
+	to   State
+}
+
+func (e *transitionError) Error() string {
+	return fmt.Sprintf("illegal state transition: %s to %s", e.from, e.to)
 }
 
-// trip opens the breaker
-func (b *Breaker) trip() {
-	b.state = StateOpen
-	b.notify(StateOpen)
+// canTransition reports whether the breaker may move between the two
+// states.
+func canTransition(from, to State) bool {
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// transition moves the breaker into a new state and notifies
+// subscribers. Illegal transitions return an error, are counted by
+// IllegalTransitions and leave the breaker unchanged.
+func (b *Breaker) transition(to State) error {
+	from := b.state
+	if canTransition(from, to) == false {
+		b.illegal++
+		return &transitionError{from: from, to: to}
+	}
+
+	now := time.Now()
+	switch {
+	case from == StatePartial && to == StateOpen:
+		b.retrips++
+	case to == StateClosed:
+		b.retrips = 0
+	}
+
+	if to == StateClosed && from != StateClosed {
+		b.startRamp()
+	}
+
+	if to == StateOpen {
+		b.jitterSample = rand.Float64() * b.jitter
+		b.openedAt = now
+		b.recordTrip(b.openedAt)
+
+		if b.tripCh != nil {
+			close(b.tripCh)
+			b.tripCh = nil
+		}
+	}
+
+	b.stateTime[from] += now.Sub(b.enteredAt)
+	b.state = to
+	b.changedAt, b.enteredAt = now, now
+	b.notify(to)
+	b.emit(func(o Observer) { o.OnStateChange(from, to) })
+	return nil
+}
+
+// IllegalTransitions returns the number of state transitions the
+// breaker has refused because they are not allowed by its state machine.
+// A non-zero value indicates a bug in the breaker or in code that moves
+// it between states.
+func (b *Breaker) IllegalTransitions() int {
+	b.lock()
+	defer b.mu.Unlock()
+	return b.illegal
+}
+
+// isReady reports whether the breaker would allow a call through.
+func (b *Breaker) isReady() bool {
+	b.lock()
+	defer b.mu.Unlock()
+	return b.ready()
+}
+
+// ready reports whether the breaker would allow a call through. It must
+// be called with the lock held.
+func (b *Breaker) ready() bool {
+	switch b.state {
+	case StateOpen:
+		return b.forced == false && b.shouldReset() && b.dampened() == false
+	case StatePartial:
+		return b.probesFull() == false
+	default:
+		return true
+	}
+}
+
+// reject records a call rejected by the breaker. It must be called with
+// the lock held.
+func (b *Breaker) reject() {
+	b.lastAttempt = time.Now()
+	b.rejectedCount++
+	b.burn(false, true)
+	if b.sampled() {
+		b.emit(func(o Observer) { o.OnCallRejected() })
+	}
+}
+
+// probesFull reports whether the maximum number of concurrent probes in
+// the partially open state are in flight.
+func (b *Breaker) probesFull() bool {
+	return b.maxProbes > 0 && b.probes >= b.maxProbes
+}
+
+// tripped returns a channel that is closed the next time the breaker
+// trips.
+func (b *Breaker) tripped() <-chan struct{} {
+	b.lock()
+	defer b.mu.Unlock()
+	if b.tripCh == nil {
+		b.tripCh = make(chan struct{})
+	}
+	return b.tripCh
 }
 
 // Protect wraps a function that returns an error with the circuit
@@ -128,75 +499,629 @@ func (b *Breaker) trip() {
 // failure counter. If a success is returned, the breaker increments
 // the success counter.
 //
-// If the breaker is open, an error is returned indicating the current
-// state of the breaker.
-func (b *Breaker) Protect(f func() error) error {
+// If the breaker is open, ErrOpen is returned and the function is not
+// called. Options change how an individual call is treated.
+func (b *Breaker) Protect(f func() error, opts ...CallOption) error {
+	return b.ProtectContext(context.Background(), func(context.Context) error {
+		return f()
+	}, opts...)
+}
 
-	// if the breaker is open and we are ready to reset then enter the
-	// partially open state
-	if b.CurrentState() == StateOpen {
-		if b.shouldReset() == false {
-			return errors.New("breaker open")
-		}
-		b.partial()
+// ProtectContext wraps a function that accepts a context with the
+// circuit breaker. The context is passed to the protected function so
+// that cancellation and deadlines propagate to the protected system.
+//
+// If the context is done before the call is made, the context's error is
+// returned and the function is not called. If the protected function
+// returns an error after the caller cancelled the context, the call is
+// not counted as a failure since it says nothing about the health of the
+// protected system. Exceeded deadlines are counted as failures.
+//
+// The context passed to the protected function is marked so that a
+// recursive call to ProtectContext on the same breaker, for example from
+// layered middleware, can be detected. Such a call is made directly,
+// without being admitted or counted, since its outcome is accounted for
+// by the outer call. This avoids counting a single failure twice and
+// probes deadlocking against the limit set by MaxProbes.
+func (b *Breaker) ProtectContext(ctx context.Context, f func(ctx context.Context) error, opts ...CallOption) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// a call made from within a call protected by the same breaker is
+	// accounted for by the outer call
+	if b.active(ctx) {
+		return f(ctx)
+	}
+
+	// a disabled breaker passes calls straight through
+	if b.isDisabled() {
+		return f(ctx)
+	}
+
+	o := newCallOptions(opts)
+	a, err := b.admit(o, 1)
+	if err != nil {
+		return err
 	}
 
 	// pass through the next request and handle the response based on
 	// the current state of the breaker
-	err := f()
-	if err != nil {
-		b.fail()
+	start := time.Now()
+	err = call(b.mark(ctx), f)
+	return b.complete(ctx, o, a, time.Since(start), err)
+}
+
+// activeKey marks a context passed to a function protected by b.
+type activeKey struct {
+	b *Breaker
+}
+
+// mark returns ctx marked as passed to a function protected by the
+// breaker. Every entry point that calls a protected function must pass
+// it a marked context, so that a recursive call on the same breaker can
+// be detected with active.
+func (b *Breaker) mark(ctx context.Context) context.Context {
+	return context.WithValue(ctx, activeKey{b}, true)
+}
+
+// active reports whether ctx was passed to a function protected by the
+// breaker, meaning that a call made with it is already accounted for.
+func (b *Breaker) active(ctx context.Context) bool {
+	return ctx.Value(activeKey{b}) != nil
+}
+
+// admission describes how a call was admitted by the breaker.
+type admission struct {
+	// probe is set for calls admitted in the partially open state
+	probe bool
+
+	// epoch is the counter epoch at the time of admission, identifying
+	// the partially open period a probe belongs to
+	epoch int
+}
+
+// admit decides whether n calls may proceed, returning ErrOpen if they
+// may not. It reports whether the calls are probes made in the partially
+// open state.
+func (b *Breaker) admit(o callOptions, n int) (admission, error) {
+	b.lock()
+	defer b.unlock()
 
-		if b.CurrentState() == StatePartial {
-			b.trip()
+	// if the breaker is open and we are ready to reset then enter the
+	// partially open state; a call made as a probe doesn't wait
+	if b.state == StateOpen {
+		ready := o.probe || b.shouldReset() && b.dampened() == false
+		if ready == false || b.permit(StatePartial) == false {
+			b.reject()
+			return admission{}, b.openError()
 		}
+		b.resetCounters()
+		b.transition(StatePartial)
+	}
+
+	// limit the rate of calls while ramping up after closing
+	if b.state == StateClosed && b.coldStarting(n) {
+		b.reject()
+		err := b.openError()
+		err.ColdStart = true
+		return admission{}, err
+	}
+
+	// limit the number of concurrent probes in the partially open state
+	probe := b.state == StatePartial
+	if probe && o.probe == false && b.maxProbes > 0 && b.probes+n > b.maxProbes {
+		b.reject()
+		return admission{}, b.openError()
+	}
+	if probe {
+		b.probes += n
+	}
+	return admission{probe: probe, epoch: b.epoch}, nil
+}
+
+// call calls the protected function, converting a panic into a
+// *PanicError.
+func call(ctx context.Context, f func(ctx context.Context) error) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Value: v, Stack: debug.Stack()}
+		}
+	}()
+	return f(ctx)
+}
+
+// complete records the outcome of a call admitted by admit and returns
+// the error to pass back to the caller.
+func (b *Breaker) complete(ctx context.Context, o callOptions, a admission, elapsed time.Duration, err error) error {
+	b.lock()
+	defer b.unlock()
+
+	// a panic is recorded as a failure and, unless panics are being
+	// recovered, raised again with its original value once the lock has
+	// been released
+	if pe, ok := err.(*PanicError); ok && b.recoverPanics == false {
+		defer panic(pe.Value)
+	}
+
+	if a.probe {
+		b.probes--
+	}
+
+	if b.sampled() {
+		b.emit(func(o Observer) { o.OnCallCompleted(err) })
+	}
+
+	// a call abandoned by the caller is neither a success nor a failure
+	if err != nil && errors.Is(ctx.Err(), context.Canceled) {
+		return err
+	}
 
-		if b.shouldTrip() == true {
-			b.trip()
+	// some calls are kept out of the breaker's statistics
+	if o.noAccount || o.failureOnly && (err == nil || err == errDegraded) {
+		if err == errDegraded {
+			return nil
 		}
+		return err
+	}
+
+	return b.outcome(err, elapsed, a)
+}
+
+// outcome records the result of a transaction admitted as described by
+// a and returns the error to pass back to the caller. It must be called
+// with the lock held.
+func (b *Breaker) outcome(err error, elapsed time.Duration, a admission) error {
+	if elapsed > 0 {
+		b.observeLatency(elapsed)
+	}
+
+	// a call served by a fallback is recorded separately
+	if err == errDegraded {
+		b.degradedCount++
+		b.counted(ResultDegraded)
+		return nil
+	}
 
+	// a successful call that takes too long counts as a failure
+	slow := err == nil && b.slowCall > 0 && elapsed > b.slowCall
+	if slow {
+		b.slowCount++
+	}
+
+	failed := err != nil || slow
+	b.burn(failed, false)
+	if failed {
+		b.counted(ResultFailure)
+	} else {
+		b.counted(ResultSuccess)
+	}
+	// only a probe admitted in the current partially open period decides
+	// its outcome; a call admitted earlier says nothing about recovery
+	if b.state == StatePartial && a.probe && a.epoch == b.epoch {
+		b.settle(failed)
 		return err
 	}
 
-	// if we are in the partial state then reset the breaker
-	if b.CurrentState() == StatePartial {
-		b.Reset()
+	// a call that was in flight when the breaker tripped completes while
+	// it is open or partially open and must not move it on
+	if b.state != StateClosed {
+		if failed {
+			b.fail()
+		} else {
+			b.success()
+		}
+		return err
+	}
+
+	if failed {
+		b.fail()
+		if b.shouldTrip() == true && b.permit(StateOpen) {
+			b.transition(StateOpen)
+		}
+		return err
 	}
 
 	b.success()
+
+	// some trip policies depend on successful calls as well as failures
+	if b.shouldTrip() == true && b.permit(StateOpen) {
+		b.transition(StateOpen)
+	}
+
 	return nil
 }
 
+// settle records the outcome of a probe and decides the outcome of the
+// partially open state. The breaker trips again as soon as ReopenAfter
+// probes have failed, and closes on a successful probe once CloseAfter
+// probes have completed without doing so. The probe that closes the
+// breaker is counted after the counters are reset. It must be called
+// with the lock held.
+func (b *Breaker) settle(failed bool) {
+	if failed {
+		b.probeFails++
+	} else {
+		b.probeSuccesses++
+	}
+	reopen := b.probeFails >= b.reopenAfter
+	closing := failed == false && reopen == false && b.probeFails+b.probeSuccesses >= b.closeAfter && b.permit(StateClosed)
+
+	if closing {
+		b.resetCounters()
+		b.transition(StateClosed)
+	}
+
+	if failed {
+		b.fail()
+	} else {
+		b.success()
+	}
+
+	if reopen && b.permit(StateOpen) {
+		b.transition(StateOpen)
+	}
+}
+
+// PanicError is returned by Protect when the protected function panics
+// and the breaker has been configured with RecoverPanics.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+
+	// Stack is the stack trace of the goroutine at the time of the panic.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("protected function panicked: %v", e.Value)
+}
+
+// RecoverPanics configures how the breaker handles a panic in the
+// protected function. A panic is always recorded as a failed transaction
+// before the breaker decides what to do with it. By default the panic is
+// then raised again in the calling goroutine. If enabled is true the
+// panic is instead returned to the caller as a *PanicError.
+//
+// A panic raised again carries the value originally passed to panic, but
+// the stack trace printed if it is not recovered starts in the breaker
+// rather than the protected function. The original stack trace is only
+// available from the Stack field of a *PanicError, when enabled is true.
+func (b *Breaker) RecoverPanics(enabled bool) *Breaker {
+	return b.configure(func() {
+		b.recoverPanics = enabled
+	})
+}
+
 // TripAfter configures the breaker to trip after n failed transactions.
 // Note that these failed transactions do not need to occur consecutively.
 func (b *Breaker) TripAfter(n int) *Breaker {
+	return b.configure(func() {
+		b.setTripAfter(n)
+	})
+}
+
+// setTripAfter applies the policy configured by TripAfter.
+func (b *Breaker) setTripAfter(n int) {
+	b.setTripPolicy(n, 0, 0, 0, 0)
 	b.shouldTrip = func() bool {
-		return b.FailCount() >= n
+		return b.recentFailures() >= n
 	}
-	return b
+}
+
+// MaxFailureAge configures the breaker to disregard failures older than
+// t when deciding whether to trip. This prevents failures spread thinly
+// over a long period from eventually tripping a breaker configured with
+// TripAfter. The value returned by FailCount is unaffected.
+func (b *Breaker) MaxFailureAge(t time.Duration) *Breaker {
+	return b.configure(func() {
+		b.maxFailAge = t
+	})
+}
+
+// TripOnSuccessRate configures the breaker to trip when the proportion
+// of successful transactions stays below rate for a sustained period.
+// The success rate is measured over consecutive periods of length t and
+// the breaker trips at the end of any period in which it falls below
+// rate. This catches slow-burn degradation that never produces a burst
+// of failures large enough to trip a breaker configured with TripAfter.
+func (b *Breaker) TripOnSuccessRate(rate float64, t time.Duration) *Breaker {
+	return b.configure(func() {
+		b.setTripPolicy(0, rate, t, 0, 0)
+
+		// lifetime totals at the start of the current period
+		var (
+			start  time.Time
+			epoch  int
+			fails  int
+			totals int
+		)
+
+		b.shouldTrip = func() bool {
+			now := time.Now()
+			total := b.totalFail + b.totalSuccess
+
+			// start a new period on first use and whenever the counters have
+			// been reset
+			if start.IsZero() || epoch != b.epoch {
+				start, epoch, fails, totals = now, b.epoch, b.totalFail, total
+				return false
+			}
+
+			if now.Sub(start) < t {
+				return false
+			}
+
+			calls := total - totals
+			successes := calls - (b.totalFail - fails)
+			start, fails, totals = now, b.totalFail, total
+
+			return calls > 0 && float64(successes)/float64(calls) < rate
+		}
+	})
+}
+
+// TripOnRate configures the breaker to trip when the proportion of
+// failed transactions reaches rate, a value between 0 and 1. The rate is
+// not evaluated until at least minRequests transactions have been
+// recorded, so that a handful of early failures cannot trip the breaker.
+// Transactions are counted since the breaker last closed.
+func (b *Breaker) TripOnRate(rate float64, minRequests int) *Breaker {
+	return b.configure(func() {
+		b.setTripPolicy(0, 0, 0, rate, minRequests)
+		b.shouldTrip = func() bool {
+			total := b.failCount + b.successCount
+			if total == 0 || total < minRequests {
+				return false
+			}
+			return float64(b.failCount)/float64(total) >= rate
+		}
+	})
+}
+
+// CloseAfter configures the number of probes that must complete in the
+// partially open state before a successful probe closes the breaker. By
+// default any failure in the partially open state trips the breaker
+// again, so CloseAfter(n) requires n consecutive successful calls; see
+// ReopenAfter to tolerate some failures. Requiring more than one success
+// reduces flapping against a dependency that is still degraded. Values
+// below one are treated as one.
+func (b *Breaker) CloseAfter(n int) *Breaker {
+	return b.configure(func() {
+		b.closeAfter = max(n, 1)
+	})
+}
+
+// ReopenAfter configures the number of failed probes that trip the
+// breaker again from the partially open state. By default the first
+// failure reopens the breaker. A larger value tolerates some failures
+// among the probes counted by CloseAfter, so with ReopenAfter(2) and
+// CloseAfter(5) the breaker reopens on the second failure and closes on
+// a successful probe once five probes have completed with at most one
+// failure. A failed probe never closes the breaker. Values below one are
+// treated as one.
+func (b *Breaker) ReopenAfter(k int) *Breaker {
+	return b.configure(func() {
+		b.reopenAfter = max(k, 1)
+	})
+}
+
+// MaxProbes limits the number of calls allowed through concurrently while
+// the breaker is partially open. Further calls are rejected with ErrOpen
+// until a probe completes, so a recovering system is not overwhelmed by
+// every waiting caller at once. A value less than one, the default,
+// allows any number of probes.
+func (b *Breaker) MaxProbes(n int) *Breaker {
+	return b.configure(func() {
+		b.maxProbes = n
+	})
+}
+
+// setTripPolicy records the settings of the current trip policy,
+// clearing those of any policy it replaces.
+func (b *Breaker) setTripPolicy(n int, successRate float64, period time.Duration, failRate float64, minRequests int) {
+	b.tripAfter = n
+	b.tripRate, b.tripPeriod = successRate, period
+	b.failRate, b.minRequests = failRate, minRequests
+	b.bucketCap, b.bucketLeak = 0, 0
 }
 
 // ResetAfter configures the breaker to reset after a period of time since
-// the last failure.
+// the last failure. The event from which the period is measured can be
+// changed with ResetTimerFrom.
 func (b *Breaker) ResetAfter(t time.Duration) *Breaker {
+	return b.configure(func() {
+		b.setResetAfter(t)
+	})
+}
+
+// setResetAfter applies the policy configured by ResetAfter.
+func (b *Breaker) setResetAfter(t time.Duration) {
+	b.resetAfter = t
 	b.shouldReset = func() bool {
-		resetTime := b.lastFail.Add(t)
+		resetTime := b.resetAnchor().Add(b.resetTimeout(t))
 		if time.Now().After(resetTime) {
 			return true
 		}
 		return false
 	}
-	return b
+}
+
+// RetryAfter returns the time remaining until the breaker will next let
+// a probe call through, or zero if it is not open. Callers can use it to
+// schedule a retry rather than polling Protect. The time includes any
+// backoff, jitter or flapping dampening in effect. While calls are
+// limited by ColdStart, it is the time until the next call is admitted. A breaker pinned open
+// by ForceOpen reports the time it would wait if it were not pinned.
+func (b *Breaker) RetryAfter() time.Duration {
+	b.lock()
+	defer b.mu.Unlock()
+	return b.retryAfter()
+}
+
+// retryAfter returns how long it will be until the breaker next lets a
+// call through, or zero if it isn't open or limited by ColdStart. It
+// must be called with the lock held.
+func (b *Breaker) retryAfter() time.Duration {
+	if b.state == StateClosed {
+		return b.rampWait()
+	}
+	if b.state != StateOpen {
+		return 0
+	}
+
+	d := time.Until(b.resetAnchor().Add(b.resetTimeout(b.resetAfter)))
+	if b.dampened() {
+		d = max(d, b.flapDampen-time.Since(b.openedAt))
+	}
+	return max(d, 0)
+}
+
+// ResetBackoff configures the breaker to double the reset timeout each
+// time it trips again from the partially open state, up to a maximum of
+// max. A persistently failing system is then probed progressively less
+// often. The timeout returns to the value configured with ResetAfter once
+// the breaker closes. A max of zero, the default, disables the backoff.
+func (b *Breaker) ResetBackoff(max time.Duration) *Breaker {
+	return b.configure(func() {
+		b.backoffMax = max
+	})
+}
+
+// resetTimeout returns the reset timeout to apply given the configured
+// base timeout.
+func (b *Breaker) resetTimeout(t time.Duration) time.Duration {
+	if b.backoffMax > 0 {
+		for i := 0; i < b.retrips && t < b.backoffMax; i++ {
+			t *= 2
+		}
+		t = min(t, b.backoffMax)
+	}
+
+	return t + time.Duration(b.jitterSample*float64(t))
+}
+
+// ResetJitter configures the breaker to extend the reset timeout by a
+// random amount of up to fraction of the timeout, chosen each time the
+// breaker trips. When many instances of a service trip at the same
+// moment, jitter spreads their probes out rather than letting them all
+// arrive at the recovering system together.
+func (b *Breaker) ResetJitter(fraction float64) *Breaker {
+	return b.configure(func() {
+		b.jitter = fraction
+	})
+}
+
+// Anchor identifies the event from which the reset timeout is measured.
+type Anchor int
+
+// Reset timer anchors
+const (
+	// AnchorLastFailure measures the reset timeout from the most recent
+	// failed transaction. This is the default.
+	AnchorLastFailure Anchor = iota
+
+	// AnchorTrip measures the reset timeout from the moment the breaker
+	// tripped, ignoring failures reported by calls still in flight.
+	AnchorTrip
+
+	// AnchorLastAttempt measures the reset timeout from the most recent
+	// call rejected by the open breaker, so the breaker only resets once
+	// callers have stopped trying for the full timeout.
+	AnchorLastAttempt
+)
+
+func (a Anchor) String() string {
+	switch a {
+	case AnchorLastFailure:
+		return "last_failure"
+	case AnchorTrip:
+		return "trip"
+	case AnchorLastAttempt:
+		return "last_attempt"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler so that anchors are
+// serialised by name.
+func (a Anchor) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// ResetTimerFrom configures the event from which the reset timeout is
+// measured. Measuring from the trip time suits user-facing systems that
+// want a predictable recovery probe; measuring from the last attempt
+// suits batch systems that should back off while work keeps arriving.
+func (b *Breaker) ResetTimerFrom(a Anchor) *Breaker {
+	return b.configure(func() {
+		b.anchor = a
+	})
+}
+
+// resetAnchor returns the time from which the reset timeout is measured
+func (b *Breaker) resetAnchor() time.Time {
+	switch b.anchor {
+	case AnchorTrip:
+		return b.openedAt
+	case AnchorLastAttempt:
+		if b.lastAttempt.After(b.openedAt) {
+			return b.lastAttempt
+		}
+		return b.openedAt
+	default:
+		if b.openedAt.After(b.lastFail) {
+			return b.openedAt
+		}
+		return b.lastFail
+	}
 }
 
 // Subscribe returns a channel on which consumers can receive notifications
 // on state change.
 func (b *Breaker) Subscribe() chan State {
+	b.lock()
+	defer b.mu.Unlock()
 	c := make(chan State, 1)
 	b.subscribers = append(b.subscribers, c)
 	return c
 }
 
+// Watch returns an iterator over state changes. Iteration continues until
+// the loop is exited or ctx is done. As with Subscribe, notifications are
+// dropped if the loop body is still handling an earlier one.
+//
+//	for s := range cb.Watch(ctx) {
+//		log.Println(s)
+//	}
+func (b *Breaker) Watch(ctx context.Context) iter.Seq[State] {
+	return func(yield func(State) bool) {
+		c := b.Subscribe()
+		defer b.unsubscribe(c)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case s := <-c:
+				if !yield(s) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// unsubscribe stops notifications to a channel returned by Subscribe
+func (b *Breaker) unsubscribe(c chan State) {
+	b.lock()
+	defer b.mu.Unlock()
+	b.subscribers = slices.DeleteFunc(b.subscribers, func(s chan State) bool {
+		return s == c
+	})
+}
+
 func (b *Breaker) notify(state State) {
 	for _, s := range b.subscribers {
 		if len(s) < cap(s) {
@@ -1,6 +1,7 @@
 package breaker
 
 import (
+	"context"
 	"errors"
 	"log"
 	"testing"
@@ -49,6 +50,49 @@ func TestNewBreaker(t *testing.T) {
 	}
 }
 
+func TestZeroValue(t *testing.T) {
+	var cb Breaker
+
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected initial state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+
+	if cb.Config() != NewBreaker().Config() {
+		t.Fatalf("unexpected config: want %+v, got %+v", NewBreaker().Config(), cb.Config())
+	}
+
+	for i := 0; i < 5; i++ {
+		cb.Protect(errorFunc)
+	}
+
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+}
+
+func TestZeroValueEmbedded(t *testing.T) {
+	var client struct {
+		Breaker
+		name string
+	}
+
+	// configuration applied before first use keeps the remaining defaults
+	client.TripAfter(1)
+	c := client.Subscribe()
+
+	if err := client.Protect(errorFunc); err == nil {
+		t.Fatalf("unexpected error: want error, got nil")
+	}
+
+	if s := <-c; s != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, s)
+	}
+
+	if client.Config().ResetAfter != 50*time.Millisecond {
+		t.Fatalf("unexpected reset timeout: want %v, got %v", 50*time.Millisecond, client.Config().ResetAfter)
+	}
+}
+
 func ExampleNewBreaker() {
 	cb := NewBreaker()
 
@@ -115,6 +159,101 @@ func TestReset(t *testing.T) {
 	}
 }
 
+func TestResetCounters(t *testing.T) {
+	cb := NewBreaker().TripAfter(1)
+	cb.Protect(errorFunc)
+	c := cb.Subscribe()
+
+	cb.ResetCounters()
+
+	if cb.FailCount() != 0 {
+		t.Fatalf("unexpected final fail count: want %d, got %d", 0, cb.FailCount())
+	}
+
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected final state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+
+	if len(c) != 0 {
+		t.Fatalf("unexpected notification count: want %d, got %d", 0, len(c))
+	}
+}
+
+func TestClose(t *testing.T) {
+	cb := NewBreaker().TripAfter(1)
+	cb.Protect(errorFunc)
+	c := cb.Subscribe()
+
+	cb.Close()
+
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+
+	if cb.FailCount() != 1 {
+		t.Fatalf("unexpected fail count: want %d, got %d", 1, cb.FailCount())
+	}
+
+	if s := <-c; s != StateClosed {
+		t.Fatalf("unexpected notification received: want %s, got %s", StateClosed, s)
+	}
+
+	// closing an already closed breaker should not notify
+	cb.Close()
+	if len(c) != 0 {
+		t.Fatalf("unexpected notification count: want %d, got %d", 0, len(c))
+	}
+
+	// the kept counters trip the breaker on the next failure unless they
+	// are reset
+	cb = NewBreaker().TripAfter(2)
+	cb.Protect(errorFunc)
+	cb.Protect(errorFunc)
+	cb.Close()
+	cb.Protect(errorFunc)
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+
+	cb.Close()
+	cb.ResetCounters()
+	cb.Protect(errorFunc)
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+}
+
+func TestOpen(t *testing.T) {
+	cb := NewBreaker().ResetAfter(20 * time.Millisecond)
+	c := cb.Subscribe()
+
+	cb.Open()
+
+	if s := <-c; s != StateOpen {
+		t.Fatalf("unexpected notification received: want %s, got %s", StateOpen, s)
+	}
+
+	if cb.FailCount() != 0 {
+		t.Fatalf("unexpected fail count: want %d, got %d", 0, cb.FailCount())
+	}
+
+	err := cb.Protect(successFunc)
+	if err == nil {
+		t.Fatalf("unexpected response: no error returned")
+	}
+
+	time.Sleep(25 * time.Millisecond)
+
+	err = cb.Protect(successFunc)
+	if err != nil {
+		t.Fatalf("unexpected response: %v", err)
+	}
+
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected final state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+}
+
 func TestProtectError(t *testing.T) {
 	cb := NewBreaker()
 
@@ -156,6 +295,191 @@ func TestProtectSuccess(t *testing.T) {
 	}
 }
 
+func TestProtectContext(t *testing.T) {
+	cb := NewBreaker()
+
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "value")
+
+	err := cb.ProtectContext(ctx, func(ctx context.Context) error {
+		if ctx.Value(key{}) != "value" {
+			return errors.New("context not propagated")
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected response: %v", err)
+	}
+
+	if cb.SuccessCount() != 1 {
+		t.Fatalf("unexpected success count: want %d, got %d", 1, cb.SuccessCount())
+	}
+}
+
+func TestProtectContextCancelled(t *testing.T) {
+	cb := NewBreaker()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := cb.ProtectContext(ctx, func(ctx context.Context) error {
+		cancel()
+		return ctx.Err()
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected error: want %v, got %v", context.Canceled, err)
+	}
+
+	if cb.FailCount() != 0 {
+		t.Fatalf("unexpected fail count: want %d, got %d", 0, cb.FailCount())
+	}
+
+	// a call with a context that is already done is not attempted
+	called := false
+	err = cb.ProtectContext(ctx, func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	if !errors.Is(err, context.Canceled) || called {
+		t.Fatalf("unexpected response: want %v without calling the function, got %v", context.Canceled, err)
+	}
+}
+
+func TestProtectContextDeadline(t *testing.T) {
+	cb := NewBreaker()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	err := cb.ProtectContext(ctx, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("unexpected error: want %v, got %v", context.DeadlineExceeded, err)
+	}
+
+	if cb.FailCount() != 1 {
+		t.Fatalf("unexpected fail count: want %d, got %d", 1, cb.FailCount())
+	}
+}
+
+func TestProtectContextRecursive(t *testing.T) {
+	cb := NewBreaker().TripAfter(2)
+	other := NewBreaker()
+
+	err := cb.ProtectContext(context.Background(), func(ctx context.Context) error {
+		// a call to a different breaker is counted as usual
+		other.ProtectContext(ctx, func(ctx context.Context) error {
+			return nil
+		})
+
+		return cb.ProtectContext(ctx, func(ctx context.Context) error {
+			return errors.New("error")
+		})
+	})
+
+	if err == nil {
+		t.Fatalf("unexpected error: want error, got nil")
+	}
+
+	if cb.FailCount() != 1 {
+		t.Fatalf("unexpected fail count: want %d, got %d", 1, cb.FailCount())
+	}
+
+	if other.SuccessCount() != 1 {
+		t.Fatalf("unexpected success count: want %d, got %d", 1, other.SuccessCount())
+	}
+
+	// a recursive probe doesn't count against the probe limit
+	cb = NewBreaker().TripAfter(1).ResetAfter(10 * time.Millisecond).MaxProbes(1)
+	cb.Protect(errorFunc)
+	time.Sleep(20 * time.Millisecond)
+
+	err = cb.ProtectContext(context.Background(), func(ctx context.Context) error {
+		return cb.ProtectContext(ctx, func(ctx context.Context) error {
+			return nil
+		})
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+}
+
+func TestProtectDegraded(t *testing.T) {
+	cb := NewBreaker()
+
+	err := cb.Protect(func() error {
+		return Degraded()
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected response: %v", err)
+	}
+
+	if cb.DegradedCount() != 1 {
+		t.Fatalf("unexpected degraded count: want %d, got %d", 1, cb.DegradedCount())
+	}
+
+	if cb.SuccessCount() != 0 {
+		t.Fatalf("unexpected success count: want %d, got %d", 0, cb.SuccessCount())
+	}
+
+	if cb.FailCount() != 0 {
+		t.Fatalf("unexpected fail count: want %d, got %d", 0, cb.FailCount())
+	}
+}
+
+func TestProtectPanic(t *testing.T) {
+	cb := NewBreaker().TripAfter(1)
+
+	defer func() {
+		v := recover()
+		if v != "protected service panic" {
+			t.Fatalf("unexpected panic value: want %v, got %v", "protected service panic", v)
+		}
+
+		if cb.FailCount() != 1 {
+			t.Fatalf("unexpected fail count: want %d, got %d", 1, cb.FailCount())
+		}
+
+		if cb.CurrentState() != StateOpen {
+			t.Fatalf("unexpected final state: want %v, got %v", StateOpen, cb.CurrentState())
+		}
+	}()
+
+	cb.Protect(func() error {
+		panic("protected service panic")
+	})
+}
+
+func TestRecoverPanics(t *testing.T) {
+	cb := NewBreaker().RecoverPanics(true)
+
+	err := cb.Protect(func() error {
+		panic("protected service panic")
+	})
+
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		t.Fatalf("unexpected error type: want %T, got %T", pe, err)
+	}
+
+	if pe.Value != "protected service panic" {
+		t.Fatalf("unexpected panic value: want %v, got %v", "protected service panic", pe.Value)
+	}
+
+	if cb.FailCount() != 1 {
+		t.Fatalf("unexpected fail count: want %d, got %d", 1, cb.FailCount())
+	}
+}
+
 func TestTripAfter(t *testing.T) {
 	cb := NewBreaker().TripAfter(6)
 
@@ -185,8 +509,124 @@ func TestTripAfter(t *testing.T) {
 	err := cb.Protect(func() error {
 		return errorFunc()
 	})
-	if err == nil {
-		t.Fatalf("unexpected response: no error returned")
+	if !errors.Is(err, ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+}
+
+func TestTripOnRate(t *testing.T) {
+	cb := NewBreaker().TripOnRate(0.5, 4)
+
+	outcomes := []bool{false, false, false}
+
+	for _, o := range outcomes {
+		cb.Protect(func() error {
+			if o {
+				return successFunc()
+			}
+			return errorFunc()
+		})
+	}
+
+	// the minimum number of requests has not been reached
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+
+	cb.Protect(successFunc)
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected final state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+}
+
+func TestTripOnRateBelowThreshold(t *testing.T) {
+	cb := NewBreaker().TripOnRate(0.5, 4)
+
+	outcomes := []bool{true, false, true, true, false, true}
+
+	for _, o := range outcomes {
+		cb.Protect(func() error {
+			if o {
+				return successFunc()
+			}
+			return errorFunc()
+		})
+	}
+
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected final state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+}
+
+func TestMaxFailureAge(t *testing.T) {
+	cb := NewBreaker().TripAfter(2).MaxFailureAge(20 * time.Millisecond)
+
+	cb.Protect(errorFunc)
+
+	// wait for the first failure to age out
+	time.Sleep(30 * time.Millisecond)
+
+	cb.Protect(errorFunc)
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+
+	if cb.FailCount() != 2 {
+		t.Fatalf("unexpected fail count: want %d, got %d", 2, cb.FailCount())
+	}
+
+	cb.Protect(errorFunc)
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected final state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+}
+
+func TestTripOnSuccessRate(t *testing.T) {
+	cb := NewBreaker().TripOnSuccessRate(0.9, 20*time.Millisecond)
+
+	outcomes := []bool{true, true, false, true, false}
+
+	for _, o := range outcomes {
+		cb.Protect(func() error {
+			if o {
+				return successFunc()
+			}
+			return errorFunc()
+		})
+	}
+
+	// the breaker should stay closed until the end of the period
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	cb.Protect(successFunc)
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected final state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+}
+
+func TestTripOnSuccessRateHealthy(t *testing.T) {
+	cb := NewBreaker().TripOnSuccessRate(0.5, 20*time.Millisecond)
+
+	outcomes := []bool{true, true, false, true}
+
+	for _, o := range outcomes {
+		cb.Protect(func() error {
+			if o {
+				return successFunc()
+			}
+			return errorFunc()
+		})
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	cb.Protect(successFunc)
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected final state: want %v, got %v", StateClosed, cb.CurrentState())
 	}
 }
 
@@ -239,6 +679,290 @@ func TestResetAfterSuccess(t *testing.T) {
 	}
 }
 
+func TestCloseAfter(t *testing.T) {
+	cb := NewBreaker().TripAfter(1).ResetAfter(10 * time.Millisecond).CloseAfter(3)
+
+	cb.Protect(errorFunc)
+	time.Sleep(15 * time.Millisecond)
+
+	cb.Protect(successFunc)
+	cb.Protect(successFunc)
+
+	if cb.CurrentState() != StatePartial {
+		t.Fatalf("unexpected state: want %v, got %v", StatePartial, cb.CurrentState())
+	}
+
+	cb.Protect(successFunc)
+
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected final state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+
+	if cb.SuccessCount() != 1 {
+		t.Fatalf("unexpected final success count: want %d, got %d", 1, cb.SuccessCount())
+	}
+}
+
+func TestCloseAfterFail(t *testing.T) {
+	cb := NewBreaker().TripAfter(1).ResetAfter(10 * time.Millisecond).CloseAfter(2)
+
+	cb.Protect(errorFunc)
+	time.Sleep(15 * time.Millisecond)
+
+	cb.Protect(successFunc)
+	cb.Protect(errorFunc)
+
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected final state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+}
+
+func TestOpenSince(t *testing.T) {
+	cb := NewBreaker().TripAfter(1)
+
+	if cb.LastStateChange().IsZero() == false {
+		t.Fatalf("unexpected last state change: want zero time, got %v", cb.LastStateChange())
+	}
+	if _, open := cb.OpenSince(); open {
+		t.Fatalf("unexpected open: want closed")
+	}
+
+	before := time.Now()
+	cb.Protect(errorFunc)
+
+	since, open := cb.OpenSince()
+	if open == false {
+		t.Fatalf("unexpected open: want open")
+	}
+	if since.Before(before) {
+		t.Fatalf("unexpected open time: want after %v, got %v", before, since)
+	}
+	if cb.LastStateChange() != since {
+		t.Fatalf("unexpected last state change: want %v, got %v", since, cb.LastStateChange())
+	}
+
+	cb.Close()
+	if _, open := cb.OpenSince(); open {
+		t.Fatalf("unexpected open: want closed")
+	}
+	if cb.LastStateChange().After(since) == false {
+		t.Fatalf("unexpected last state change: want after %v, got %v", since, cb.LastStateChange())
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	cb := NewBreaker().TripAfter(1).ResetAfter(100 * time.Millisecond)
+
+	if cb.RetryAfter() != 0 {
+		t.Fatalf("unexpected retry after: want %v, got %v", 0, cb.RetryAfter())
+	}
+
+	cb.Protect(errorFunc)
+	if d := cb.RetryAfter(); d <= 50*time.Millisecond || d > 100*time.Millisecond {
+		t.Fatalf("unexpected retry after: want about %v, got %v", 100*time.Millisecond, d)
+	}
+
+	time.Sleep(110 * time.Millisecond)
+	if cb.RetryAfter() != 0 {
+		t.Fatalf("unexpected retry after: want %v, got %v", 0, cb.RetryAfter())
+	}
+}
+
+func TestReopenAfter(t *testing.T) {
+	cb := NewBreaker().TripAfter(1).ResetAfter(10 * time.Millisecond).CloseAfter(3).ReopenAfter(2)
+
+	cb.Protect(errorFunc)
+	time.Sleep(15 * time.Millisecond)
+
+	// a single failed probe is tolerated
+	cb.Protect(errorFunc)
+	cb.Protect(successFunc)
+	if cb.CurrentState() != StatePartial {
+		t.Fatalf("unexpected state: want %v, got %v", StatePartial, cb.CurrentState())
+	}
+
+	cb.Protect(successFunc)
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected final state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+
+	cb.Protect(errorFunc)
+	time.Sleep(15 * time.Millisecond)
+
+	cb.Protect(errorFunc)
+	cb.Protect(errorFunc)
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected final state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+}
+
+func TestReopenAfterFailedProbe(t *testing.T) {
+	cb := NewBreaker().TripAfter(1).ResetAfter(10 * time.Millisecond).ReopenAfter(2)
+
+	cb.Protect(errorFunc)
+	time.Sleep(15 * time.Millisecond)
+
+	// a failed probe never closes the breaker
+	cb.Protect(errorFunc)
+	if cb.CurrentState() != StatePartial {
+		t.Fatalf("unexpected state: want %v, got %v", StatePartial, cb.CurrentState())
+	}
+
+	cb.Protect(successFunc)
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected final state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+}
+
+func TestProbePolicyClamped(t *testing.T) {
+	c := NewBreaker().CloseAfter(0).ReopenAfter(-1).Config()
+	if c.CloseAfter != 1 || c.ReopenAfter != 1 {
+		t.Fatalf("unexpected probe policy: want %d of %d, got %d of %d", 1, 1, c.ReopenAfter, c.CloseAfter)
+	}
+}
+
+func TestStaleCallInPartial(t *testing.T) {
+	cb := NewBreaker().TripAfter(1).ResetAfter(10 * time.Millisecond).MaxProbes(1)
+
+	// a slow call admitted while the breaker is closed
+	staleStarted := make(chan struct{})
+	staleRelease := make(chan struct{})
+	staleDone := make(chan error)
+	go func() {
+		staleDone <- cb.Protect(func() error {
+			close(staleStarted)
+			<-staleRelease
+			return nil
+		})
+	}()
+	<-staleStarted
+
+	cb.Protect(errorFunc)
+	time.Sleep(15 * time.Millisecond)
+
+	probeStarted := make(chan struct{})
+	probeRelease := make(chan struct{})
+	probeDone := make(chan error)
+	go func() {
+		probeDone <- cb.Protect(func() error {
+			close(probeStarted)
+			<-probeRelease
+			return nil
+		})
+	}()
+	<-probeStarted
+
+	// the stale call completes without deciding the partial state
+	close(staleRelease)
+	<-staleDone
+	if cb.CurrentState() != StatePartial {
+		t.Fatalf("unexpected state: want %v, got %v", StatePartial, cb.CurrentState())
+	}
+
+	close(probeRelease)
+	if err := <-probeDone; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected final state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+}
+
+func TestMaxProbes(t *testing.T) {
+	cb := NewBreaker().TripAfter(1).ResetAfter(10 * time.Millisecond).MaxProbes(1)
+
+	cb.Protect(errorFunc)
+	time.Sleep(15 * time.Millisecond)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- cb.Protect(func() error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+
+	// a second probe is rejected while the first is in flight
+	<-started
+	err := cb.Protect(successFunc)
+	if !errors.Is(err, ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected response: %v", err)
+	}
+
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected final state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+}
+
+func TestResetBackoff(t *testing.T) {
+	cb := NewBreaker().TripAfter(1).ResetAfter(20 * time.Millisecond).ResetBackoff(60 * time.Millisecond)
+
+	timeouts := []time.Duration{}
+	cb.Protect(errorFunc)
+	for i := 0; i < 3; i++ {
+		cb.mu.Lock()
+		timeouts = append(timeouts, cb.resetTimeout(20*time.Millisecond))
+		cb.mu.Unlock()
+
+		// fail the probe so the breaker trips from the partial state
+		time.Sleep(timeouts[i] + 5*time.Millisecond)
+		cb.Protect(errorFunc)
+	}
+
+	want := []time.Duration{20 * time.Millisecond, 40 * time.Millisecond, 60 * time.Millisecond}
+	for i := range want {
+		if timeouts[i] != want[i] {
+			t.Fatalf("unexpected reset timeout after %d re-trips: want %v, got %v", i, want[i], timeouts[i])
+		}
+	}
+
+	// a rejected call confirms the longer timeout is in effect
+	time.Sleep(30 * time.Millisecond)
+	if err := cb.Protect(successFunc); !errors.Is(err, ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+
+	// closing the breaker restores the base timeout
+	cb.Reset()
+	cb.mu.Lock()
+	timeout := cb.resetTimeout(20 * time.Millisecond)
+	cb.mu.Unlock()
+	if timeout != 20*time.Millisecond {
+		t.Fatalf("unexpected reset timeout: want %v, got %v", 20*time.Millisecond, timeout)
+	}
+}
+
+func TestResetJitter(t *testing.T) {
+	base := 100 * time.Millisecond
+	seen := map[time.Duration]bool{}
+
+	for i := 0; i < 10; i++ {
+		cb := NewBreaker().ResetAfter(base).ResetJitter(0.5)
+		cb.Open()
+
+		cb.mu.Lock()
+		timeout := cb.resetTimeout(base)
+		cb.mu.Unlock()
+
+		if timeout < base || timeout > base*3/2 {
+			t.Fatalf("unexpected reset timeout: want between %v and %v, got %v", base, base*3/2, timeout)
+		}
+		seen[timeout] = true
+	}
+
+	if len(seen) < 2 {
+		t.Fatalf("unexpected reset timeouts: want varied timeouts, got %v", seen)
+	}
+}
+
 func TestResetAfterFail(t *testing.T) {
 	cb := NewBreaker().TripAfter(3)
 	outcomes := []bool{true, false, false, false}
@@ -287,6 +1011,60 @@ func TestResetAfterFail(t *testing.T) {
 	}
 }
 
+func TestResetTimerFromTrip(t *testing.T) {
+	cb := NewBreaker().TripAfter(1).ResetAfter(100 * time.Millisecond).ResetTimerFrom(AnchorTrip)
+	cb.Protect(errorFunc)
+
+	// simulate a late failure from a call that was already in flight
+	time.Sleep(60 * time.Millisecond)
+	cb.fail()
+
+	time.Sleep(50 * time.Millisecond)
+	err := cb.Protect(successFunc)
+	if err != nil {
+		t.Fatalf("unexpected response: %v", err)
+	}
+}
+
+func TestResetTimerFromLastFailure(t *testing.T) {
+	cb := NewBreaker().TripAfter(1).ResetAfter(100 * time.Millisecond)
+	cb.Protect(errorFunc)
+
+	// simulate a late failure from a call that was already in flight
+	time.Sleep(60 * time.Millisecond)
+	cb.fail()
+
+	time.Sleep(50 * time.Millisecond)
+	err := cb.Protect(successFunc)
+	if err == nil {
+		t.Fatalf("unexpected response: no error returned")
+	}
+}
+
+func TestResetTimerFromLastAttempt(t *testing.T) {
+	cb := NewBreaker().TripAfter(1).ResetAfter(100 * time.Millisecond).ResetTimerFrom(AnchorLastAttempt)
+	cb.Protect(errorFunc)
+
+	time.Sleep(60 * time.Millisecond)
+	err := cb.Protect(successFunc)
+	if err == nil {
+		t.Fatalf("unexpected response: no error returned")
+	}
+
+	// the rejected attempt restarts the reset timer
+	time.Sleep(60 * time.Millisecond)
+	err = cb.Protect(successFunc)
+	if err == nil {
+		t.Fatalf("unexpected response: no error returned")
+	}
+
+	time.Sleep(110 * time.Millisecond)
+	err = cb.Protect(successFunc)
+	if err != nil {
+		t.Fatalf("unexpected response: %v", err)
+	}
+}
+
 func TestSubscribe(t *testing.T) {
 
 	cb := NewBreaker()
@@ -295,14 +1073,14 @@ func TestSubscribe(t *testing.T) {
 	// create a second subscriber but don't drain notifications
 	cb.Subscribe()
 
-	cb.trip()
+	cb.transition(StateOpen)
 	s1 := <-c1
 
 	if s1 != StateOpen {
 		t.Fatalf("unexpected notification received: want %s, got %s", StateOpen, s1)
 	}
 
-	cb.partial()
+	cb.transition(StatePartial)
 	s1 = <-c1
 	if s1 != StatePartial {
 		t.Fatalf("unexpected notification received: want %s, got %s", StatePartial, s1)
@@ -315,6 +1093,101 @@ func TestSubscribe(t *testing.T) {
 	}
 }
 
+func TestWatch(t *testing.T) {
+	cb := NewBreaker()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan []State)
+	go func() {
+		var states []State
+		for s := range cb.Watch(ctx) {
+			states = append(states, s)
+			if len(states) == 2 {
+				break
+			}
+		}
+		done <- states
+	}()
+
+	// wait for the watcher to subscribe
+	var c chan State
+	for c == nil {
+		time.Sleep(time.Millisecond)
+		cb.mu.Lock()
+		if len(cb.subscribers) == 1 {
+			c = cb.subscribers[0]
+		}
+		cb.mu.Unlock()
+	}
+
+	// wait for each notification to be consumed before sending the next
+	cb.Open()
+	for len(c) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cb.Close()
+
+	states := <-done
+	if len(states) != 2 || states[0] != StateOpen || states[1] != StateClosed {
+		t.Fatalf("unexpected states: want %v, got %v", []State{StateOpen, StateClosed}, states)
+	}
+
+	// leaving the loop should unsubscribe the watcher
+	cb.mu.Lock()
+	n := len(cb.subscribers)
+	cb.mu.Unlock()
+	if n != 0 {
+		t.Fatalf("unexpected subscriber count: want %d, got %d", 0, n)
+	}
+}
+
+func TestTransition(t *testing.T) {
+	states := []State{StateClosed, StateOpen, StatePartial}
+
+	legal := map[State]map[State]bool{
+		StateClosed:  {StateOpen: true, StateClosed: true},
+		StateOpen:    {StatePartial: true, StateClosed: true},
+		StatePartial: {StateOpen: true, StateClosed: true},
+	}
+
+	for _, from := range states {
+		for _, to := range states {
+			cb := NewBreaker()
+			cb.state = from
+			c := cb.Subscribe()
+
+			err := cb.transition(to)
+
+			if legal[from][to] {
+				if err != nil {
+					t.Fatalf("unexpected response for %s to %s: %v", from, to, err)
+				}
+				if cb.CurrentState() != to {
+					t.Fatalf("unexpected state after %s to %s: want %v, got %v", from, to, to, cb.CurrentState())
+				}
+				if len(c) != 1 {
+					t.Fatalf("unexpected notification count after %s to %s: want %d, got %d", from, to, 1, len(c))
+				}
+				continue
+			}
+
+			if err == nil {
+				t.Fatalf("unexpected response for %s to %s: no error returned", from, to)
+			}
+			if cb.CurrentState() != from {
+				t.Fatalf("unexpected state after %s to %s: want %v, got %v", from, to, from, cb.CurrentState())
+			}
+			if len(c) != 0 {
+				t.Fatalf("unexpected notification count after %s to %s: want %d, got %d", from, to, 0, len(c))
+			}
+			if cb.IllegalTransitions() != 1 {
+				t.Fatalf("unexpected illegal transition count after %s to %s: want %d, got %d", from, to, 1, cb.IllegalTransitions())
+			}
+		}
+	}
+}
+
 func ExampleBreaker_TripAfter() {
 	cb := NewBreaker().TripAfter(5)
 
@@ -355,6 +1228,23 @@ func ExampleBreaker_Protect() {
 	}
 }
 
+func ExampleBreaker_ProtectContext() {
+	cb := NewBreaker()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err := cb.ProtectContext(ctx, func(ctx context.Context) error {
+		// make the function call you are trying to protect, passing
+		// ctx, and return an error on failure
+		return nil
+	})
+
+	if err != nil {
+		log.Println(err)
+	}
+}
+
 func ExampleBreaker_Subscribe() {
 
 	// create a circuit breaker
@@ -0,0 +1,85 @@
+/*
+Package breakergrpc provides gRPC interceptors that protect calls with
+circuit breakers.
+
+Calls are classified by the gRPC status code they return. Codes that
+indicate a problem with the server or the network, such as UNAVAILABLE
+and DEADLINE_EXCEEDED, are counted as failures, while codes describing a
+problem with the request, such as INVALID_ARGUMENT, are not. While a
+breaker is open, outgoing calls fail immediately with an UNAVAILABLE
+status and incoming calls are shed with RESOURCE_EXHAUSTED. Errors on
+established client streams can also be counted by using
+MessageStreamClientInterceptor in place of StreamClientInterceptor.
+
+	cb := breaker.NewBreaker()
+	conn, err := grpc.NewClient(target,
+		grpc.WithUnaryInterceptor(breakergrpc.UnaryClientInterceptor(cb, nil)),
+		grpc.WithStreamInterceptor(breakergrpc.StreamClientInterceptor(cb, nil)),
+	)
+*/
+package breakergrpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/billglover/breaker"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// A Classifier reports whether a call that returned the given status
+// code should be counted as a failure.
+type Classifier func(code codes.Code) bool
+
+// DefaultClassifier counts codes that indicate a problem with the server
+// or the network as failures.
+func DefaultClassifier(code codes.Code) bool {
+	switch code {
+	case codes.Unavailable,
+		codes.DeadlineExceeded,
+		codes.ResourceExhausted,
+		codes.Internal,
+		codes.Unknown,
+		codes.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
+
+// protect calls f protected by the breaker, counting errors as failures
+// only if the classifier says so. Calls rejected by the breaker fail
+// with the status code rejected.
+func protect(ctx context.Context, b *breaker.Breaker, classify Classifier, rejected codes.Code, f func(ctx context.Context) error) error {
+	if classify == nil {
+		classify = DefaultClassifier
+	}
+
+	_, err := breaker.ProtectResult(ctx, b, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, f(ctx)
+	}, func(_ struct{}, err error) bool {
+		return err != nil && classify(status.Code(err))
+	})
+
+	if errors.Is(err, breaker.ErrOpen) {
+		return &openError{err: err, code: rejected}
+	}
+	return err
+}
+
+// openError is returned for calls rejected by an open breaker. It
+// carries a gRPC status while still matching breaker.ErrOpen.
+type openError struct {
+	err  error
+	code codes.Code
+}
+
+func (e *openError) Error() string { return e.GRPCStatus().Err().Error() }
+
+func (e *openError) Unwrap() error { return e.err }
+
+// GRPCStatus returns the status sent to callers of a rejected call.
+func (e *openError) GRPCStatus() *status.Status {
+	return status.New(e.code, e.err.Error())
+}
@@ -0,0 +1,96 @@
+package breakergrpc
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/billglover/breaker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryClientInterceptor returns a client interceptor that protects
+// outgoing unary calls with the breaker b. Errors are classified using
+// classify, or DefaultClassifier if it is nil.
+func UnaryClientInterceptor(b *breaker.Breaker, classify Classifier) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return protect(ctx, b, classify, codes.Unavailable, func(ctx context.Context) error {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		})
+	}
+}
+
+// StreamClientInterceptor returns a client interceptor that protects the
+// establishment of outgoing streams with the breaker b. A failure to
+// establish a stream is counted against the breaker and new streams are
+// rejected while it is open. Errors on an established stream are not
+// counted. Errors are classified using classify, or DefaultClassifier if
+// it is nil.
+func StreamClientInterceptor(b *breaker.Breaker, classify Classifier) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		var cs grpc.ClientStream
+		err := protect(ctx, b, classify, codes.Unavailable, func(ctx context.Context) error {
+			var err error
+			cs, err = streamer(ctx, desc, cc, method, opts...)
+			return err
+		})
+		return cs, err
+	}
+}
+
+// MessageStreamClientInterceptor returns a client interceptor that
+// protects outgoing streams with the breaker b like
+// StreamClientInterceptor, and also counts errors returned by SendMsg
+// and RecvMsg on established streams. This lets long-lived streams
+// report degradation as it happens rather than only when a new stream is
+// opened. Each message error classified as a failure is recorded against
+// the breaker while it is closed; successful messages, the normal end of
+// a stream and errors while the breaker is open or partially open are
+// not recorded, so that a stream opened before an outage cannot act as a
+// probe. Established streams are never closed by the breaker.
+// Errors are classified using classify, or DefaultClassifier if it is
+// nil.
+func MessageStreamClientInterceptor(b *breaker.Breaker, classify Classifier) grpc.StreamClientInterceptor {
+	if classify == nil {
+		classify = DefaultClassifier
+	}
+	establish := StreamClientInterceptor(b, classify)
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := establish(ctx, desc, cc, method, streamer, opts...)
+		if err != nil || cs == nil {
+			return cs, err
+		}
+		return &clientStream{ClientStream: cs, b: b, classify: classify}, nil
+	}
+}
+
+// clientStream records message errors on an established stream.
+type clientStream struct {
+	grpc.ClientStream
+	b        *breaker.Breaker
+	classify Classifier
+}
+
+func (s *clientStream) SendMsg(m any) error {
+	err := s.ClientStream.SendMsg(m)
+	s.record(err)
+	return err
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	s.record(err)
+	return err
+}
+
+// record counts a message error against the breaker if it is classified
+// as a failure. io.EOF marks the end of the stream and is not an error.
+func (s *clientStream) record(err error) {
+	if err == nil || errors.Is(err, io.EOF) || s.classify(status.Code(err)) == false {
+		return
+	}
+	s.b.RecordIfClosed(breaker.Outcome{Err: err})
+}
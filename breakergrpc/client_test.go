@@ -0,0 +1,218 @@
+package breakergrpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/billglover/breaker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// invokerFunc returns an invoker that fails with the given code, or
+// succeeds if the code is OK, and counts the number of calls made.
+func invokerFunc(code codes.Code, calls *int) grpc.UnaryInvoker {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		*calls++
+		return status.Error(code, code.String())
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	cb := breaker.NewBreaker().TripAfter(2)
+	interceptor := UnaryClientInterceptor(cb, nil)
+	ctx := context.Background()
+	calls := 0
+
+	err := interceptor(ctx, "/test.Service/Method", nil, nil, nil, invokerFunc(codes.OK, &calls))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// client errors are returned but not counted as failures
+	err = interceptor(ctx, "/test.Service/Method", nil, nil, nil, invokerFunc(codes.InvalidArgument, &calls))
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("unexpected code: want %v, got %v", codes.InvalidArgument, status.Code(err))
+	}
+
+	if cb.SuccessCount() != 2 {
+		t.Fatalf("unexpected success count: want %d, got %d", 2, cb.SuccessCount())
+	}
+
+	for i := 0; i < 2; i++ {
+		err = interceptor(ctx, "/test.Service/Method", nil, nil, nil, invokerFunc(codes.Unavailable, &calls))
+		if status.Code(err) != codes.Unavailable {
+			t.Fatalf("unexpected code: want %v, got %v", codes.Unavailable, status.Code(err))
+		}
+	}
+
+	if cb.CurrentState() != breaker.StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", breaker.StateOpen, cb.CurrentState())
+	}
+
+	// calls fail fast with a status while the breaker is open
+	err = interceptor(ctx, "/test.Service/Method", nil, nil, nil, invokerFunc(codes.OK, &calls))
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("unexpected code: want %v, got %v", codes.Unavailable, status.Code(err))
+	}
+
+	if !errors.Is(err, breaker.ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", breaker.ErrOpen, err)
+	}
+
+	if calls != 4 {
+		t.Fatalf("unexpected call count: want %d, got %d", 4, calls)
+	}
+}
+
+func TestUnaryClientInterceptorClassifier(t *testing.T) {
+	cb := breaker.NewBreaker().TripAfter(1)
+	interceptor := UnaryClientInterceptor(cb, func(code codes.Code) bool {
+		return code == codes.NotFound
+	})
+	calls := 0
+
+	interceptor(context.Background(), "/test.Service/Method", nil, nil, nil, invokerFunc(codes.Unavailable, &calls))
+
+	if cb.FailCount() != 0 {
+		t.Fatalf("unexpected fail count: want %d, got %d", 0, cb.FailCount())
+	}
+
+	interceptor(context.Background(), "/test.Service/Method", nil, nil, nil, invokerFunc(codes.NotFound, &calls))
+
+	if cb.FailCount() != 1 {
+		t.Fatalf("unexpected fail count: want %d, got %d", 1, cb.FailCount())
+	}
+}
+
+// streamerFunc returns a streamer that fails with the given code, or
+// succeeds if the code is OK.
+func streamerFunc(code codes.Code) grpc.Streamer {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if code != codes.OK {
+			return nil, status.Error(code, code.String())
+		}
+		return nil, nil
+	}
+}
+
+func TestStreamClientInterceptor(t *testing.T) {
+	cb := breaker.NewBreaker().TripAfter(1)
+	interceptor := StreamClientInterceptor(cb, nil)
+	desc := &grpc.StreamDesc{StreamName: "Stream", ServerStreams: true}
+	ctx := context.Background()
+
+	if _, err := interceptor(ctx, desc, nil, "/test.Service/Stream", streamerFunc(codes.OK)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cb.SuccessCount() != 1 {
+		t.Fatalf("unexpected success count: want %d, got %d", 1, cb.SuccessCount())
+	}
+
+	_, err := interceptor(ctx, desc, nil, "/test.Service/Stream", streamerFunc(codes.Unavailable))
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("unexpected code: want %v, got %v", codes.Unavailable, status.Code(err))
+	}
+
+	if cb.CurrentState() != breaker.StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", breaker.StateOpen, cb.CurrentState())
+	}
+
+	// new streams are rejected while the breaker is open
+	_, err = interceptor(ctx, desc, nil, "/test.Service/Stream", streamerFunc(codes.OK))
+	if !errors.Is(err, breaker.ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", breaker.ErrOpen, err)
+	}
+}
+
+// fakeStream is a client stream whose messages fail with the given code,
+// or succeed if the code is OK.
+type fakeStream struct {
+	grpc.ClientStream
+	code codes.Code
+}
+
+func (s *fakeStream) SendMsg(m any) error { return status.Error(s.code, s.code.String()) }
+
+func (s *fakeStream) RecvMsg(m any) error {
+	if s.code == codes.OK {
+		return io.EOF
+	}
+	return status.Error(s.code, s.code.String())
+}
+
+func TestMessageStreamClientInterceptor(t *testing.T) {
+	cb := breaker.NewBreaker().TripAfter(2)
+	interceptor := MessageStreamClientInterceptor(cb, nil)
+	desc := &grpc.StreamDesc{StreamName: "Stream", ServerStreams: true}
+	stream := &fakeStream{code: codes.OK}
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return stream, nil
+	}
+
+	cs, err := interceptor(context.Background(), desc, nil, "/test.Service/Stream", streamer)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the end of the stream is not counted
+	if err := cs.RecvMsg(nil); err != io.EOF {
+		t.Fatalf("unexpected error: want %v, got %v", io.EOF, err)
+	}
+
+	// client errors are returned but not counted as failures
+	stream.code = codes.InvalidArgument
+	cs.SendMsg(nil)
+	if cb.FailCount() != 0 {
+		t.Fatalf("unexpected fail count: want %d, got %d", 0, cb.FailCount())
+	}
+
+	stream.code = codes.Unavailable
+	cs.SendMsg(nil)
+	if err := cs.RecvMsg(nil); status.Code(err) != codes.Unavailable {
+		t.Fatalf("unexpected code: want %v, got %v", codes.Unavailable, status.Code(err))
+	}
+
+	if cb.CurrentState() != breaker.StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", breaker.StateOpen, cb.CurrentState())
+	}
+
+	// new streams are rejected while the breaker is open
+	_, err = interceptor(context.Background(), desc, nil, "/test.Service/Stream", streamer)
+	if !errors.Is(err, breaker.ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", breaker.ErrOpen, err)
+	}
+}
+
+func TestMessageStreamClientInterceptorOpen(t *testing.T) {
+	cb := breaker.NewBreaker().TripAfter(1).ResetAfter(10 * time.Millisecond)
+	interceptor := MessageStreamClientInterceptor(cb, nil)
+	desc := &grpc.StreamDesc{StreamName: "Stream", ServerStreams: true}
+	stream := &fakeStream{code: codes.Unavailable}
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return stream, nil
+	}
+
+	cs, err := interceptor(context.Background(), desc, nil, "/test.Service/Stream", streamer)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cb.Open()
+	changes := cb.Subscribe()
+	time.Sleep(15 * time.Millisecond)
+
+	// an error on a stream opened before the outage is not a probe
+	cs.RecvMsg(nil)
+	if cb.CurrentState() != breaker.StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", breaker.StateOpen, cb.CurrentState())
+	}
+	if len(changes) != 0 {
+		t.Fatalf("unexpected state changes: want %d, got %d", 0, len(changes))
+	}
+}
@@ -0,0 +1,28 @@
+package breakergrpc
+
+import (
+	"context"
+
+	"github.com/billglover/breaker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// UnaryServerInterceptor returns a server interceptor that sheds inbound
+// unary calls using the breaker b. Errors returned by the server's
+// handlers are classified using classify, or DefaultClassifier if it is
+// nil, so that the breaker trips when the handlers are failing; combined
+// with Breaker.SlowCallThreshold it also trips when they are slow. While
+// the breaker is open, calls are rejected with RESOURCE_EXHAUSTED
+// without running the handler.
+func UnaryServerInterceptor(b *breaker.Breaker, classify Classifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var resp any
+		err := protect(ctx, b, classify, codes.ResourceExhausted, func(ctx context.Context) error {
+			var err error
+			resp, err = handler(ctx, req)
+			return err
+		})
+		return resp, err
+	}
+}
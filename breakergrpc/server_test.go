@@ -0,0 +1,51 @@
+package breakergrpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/billglover/breaker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	cb := breaker.NewBreaker().TripAfter(1)
+	interceptor := UnaryServerInterceptor(cb, nil)
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+	calls := 0
+
+	ok := func(ctx context.Context, req any) (any, error) {
+		calls++
+		return "reply", nil
+	}
+	failing := func(ctx context.Context, req any) (any, error) {
+		calls++
+		return nil, status.Error(codes.Internal, "internal")
+	}
+
+	resp, err := interceptor(context.Background(), nil, info, ok)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp != "reply" {
+		t.Fatalf("unexpected response: want %q, got %v", "reply", resp)
+	}
+
+	interceptor(context.Background(), nil, info, failing)
+
+	if cb.CurrentState() != breaker.StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", breaker.StateOpen, cb.CurrentState())
+	}
+
+	// inbound calls are shed while the breaker is open
+	_, err = interceptor(context.Background(), nil, info, ok)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("unexpected code: want %v, got %v", codes.ResourceExhausted, status.Code(err))
+	}
+
+	if calls != 2 {
+		t.Fatalf("unexpected call count: want %d, got %d", 2, calls)
+	}
+}
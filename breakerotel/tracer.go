@@ -0,0 +1,80 @@
+/*
+Package breakerotel traces calls protected by circuit breakers using
+OpenTelemetry.
+
+Each call is recorded as a child span of the span in the caller's
+context, carrying the name of the breaker, its state when the call was
+made and the outcome of the call. Calls rejected by an open breaker are
+marked with a breaker.rejected event so they can be told apart from
+calls that failed.
+
+	t := breakerotel.New("payments", payments, nil)
+	err := t.Protect(ctx, func(ctx context.Context) error {
+		return charge(ctx, order)
+	})
+*/
+package breakerotel
+
+import (
+	"context"
+	"errors"
+
+	"github.com/billglover/breaker"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentation is the name of the tracer used by this package.
+const instrumentation = "github.com/billglover/breaker/breakerotel"
+
+// Outcomes recorded in the breaker.outcome attribute.
+const (
+	OutcomeSuccess  = "success"
+	OutcomeFailure  = "failure"
+	OutcomeRejected = "rejected"
+)
+
+// Tracer protects calls with a breaker and traces them.
+type Tracer struct {
+	name   string
+	b      *breaker.Breaker
+	tracer trace.Tracer
+}
+
+// New returns a Tracer for the breaker b, identified in spans by name.
+// Spans are created using tp, or the global tracer provider if tp is
+// nil.
+func New(name string, b *breaker.Breaker, tp trace.TracerProvider) *Tracer {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return &Tracer{name: name, b: b, tracer: tp.Tracer(instrumentation)}
+}
+
+// Protect calls f protected by the breaker within a new span. The span's
+// context is passed to f.
+func (t *Tracer) Protect(ctx context.Context, f func(ctx context.Context) error) error {
+	ctx, span := t.tracer.Start(ctx, "breaker "+t.name, trace.WithAttributes(
+		attribute.String("breaker.name", t.name),
+		attribute.String("breaker.state", t.b.CurrentState().String()),
+	))
+	defer span.End()
+
+	err := t.b.ProtectContext(ctx, f)
+
+	switch {
+	case errors.Is(err, breaker.ErrOpen):
+		span.AddEvent("breaker.rejected")
+		span.SetAttributes(attribute.String("breaker.outcome", OutcomeRejected))
+		span.SetStatus(codes.Error, err.Error())
+	case err != nil:
+		span.RecordError(err)
+		span.SetAttributes(attribute.String("breaker.outcome", OutcomeFailure))
+		span.SetStatus(codes.Error, err.Error())
+	default:
+		span.SetAttributes(attribute.String("breaker.outcome", OutcomeSuccess))
+	}
+	return err
+}
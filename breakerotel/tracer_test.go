@@ -0,0 +1,70 @@
+package breakerotel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/billglover/breaker"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// outcome returns the breaker.outcome attribute of a span.
+func outcome(s sdktrace.ReadOnlySpan) string {
+	for _, kv := range s.Attributes() {
+		if kv.Key == attribute.Key("breaker.outcome") {
+			return kv.Value.AsString()
+		}
+	}
+	return ""
+}
+
+func TestTracer(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+
+	cb := breaker.NewBreaker().TripAfter(1)
+	tr := New("payments", cb, tp)
+
+	tr.Protect(context.Background(), func(ctx context.Context) error {
+		return nil
+	})
+	tr.Protect(context.Background(), func(ctx context.Context) error {
+		return errors.New("error")
+	})
+	err := tr.Protect(context.Background(), func(ctx context.Context) error {
+		return nil
+	})
+
+	if !errors.Is(err, breaker.ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", breaker.ErrOpen, err)
+	}
+
+	spans := rec.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("unexpected span count: want %d, got %d", 3, len(spans))
+	}
+
+	if spans[0].Name() != "breaker payments" {
+		t.Fatalf("unexpected span name: want %q, got %q", "breaker payments", spans[0].Name())
+	}
+
+	want := []string{OutcomeSuccess, OutcomeFailure, OutcomeRejected}
+	for i, s := range spans {
+		if got := outcome(s); got != want[i] {
+			t.Fatalf("unexpected outcome: want %q, got %q", want[i], got)
+		}
+	}
+
+	if spans[2].Status().Code != codes.Error {
+		t.Fatalf("unexpected status: want %v, got %v", codes.Error, spans[2].Status().Code)
+	}
+
+	events := spans[2].Events()
+	if len(events) != 1 || events[0].Name != "breaker.rejected" {
+		t.Fatalf("unexpected events: want %q, got %v", "breaker.rejected", events)
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Package breakerprom exposes the activity of circuit breakers as
+Prometheus metrics.
+
+A Collector observes each breaker registered with it and reports the
+following metrics, labelled with the name the breaker was registered
+under:
+
+	breaker_state{name, state}                 1 for the current state, 0 otherwise
+	breaker_calls_total{name, result}          completed calls by result, success or failure
+	breaker_rejected_calls_total{name}         calls rejected by the breaker
+	breaker_transitions_total{name, from, to}  state transitions
+
+Calls are counted using breaker observers, with the result the breaker
+counted them as, so the call counts agree with the breaker's own
+counters. Degraded calls and calls the breaker does not count, such as
+those abandoned by the caller, are not included. If a breaker samples
+its observations the call counts are sampled too.
+
+	c := breakerprom.NewCollector()
+	c.Register("payments", payments)
+	prometheus.MustRegister(c)
+*/
+package breakerprom
+
+import (
+	"sync"
+
+	"github.com/billglover/breaker"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	stateDesc = prometheus.NewDesc(
+		"breaker_state",
+		"Current state of the circuit breaker.",
+		[]string{"name", "state"}, nil,
+	)
+	callsDesc = prometheus.NewDesc(
+		"breaker_calls_total",
+		"Calls completed through the circuit breaker.",
+		[]string{"name", "result"}, nil,
+	)
+	rejectedDesc = prometheus.NewDesc(
+		"breaker_rejected_calls_total",
+		"Calls rejected by the circuit breaker.",
+		[]string{"name"}, nil,
+	)
+	transitionsDesc = prometheus.NewDesc(
+		"breaker_transitions_total",
+		"State transitions of the circuit breaker.",
+		[]string{"name", "from", "to"}, nil,
+	)
+)
+
+// states lists every state reported by the breaker_state metric.
+var states = []breaker.State{breaker.StateClosed, breaker.StatePartial, breaker.StateOpen}
+
+// Collector is a prometheus.Collector reporting metrics for a set of
+// named breakers.
+type Collector struct {
+	mu       sync.Mutex
+	breakers map[string]*counter
+}
+
+// NewCollector returns a new Collector with no breakers.
+func NewCollector() *Collector {
+	return &Collector{breakers: map[string]*counter{}}
+}
+
+// Register adds the breaker b to the collector under name. Only activity
+// after registration is counted. Registering a second breaker with the
+// same name replaces the first.
+func (c *Collector) Register(name string, b *breaker.Breaker) {
+	cnt := &counter{b: b, transitions: map[[2]breaker.State]float64{}}
+	b.RegisterObserver(cnt)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.breakers[name] = cnt
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- stateDesc
+	ch <- callsDesc
+	ch <- rejectedDesc
+	ch <- transitionsDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name, cnt := range c.breakers {
+		current := cnt.b.CurrentState()
+		for _, s := range states {
+			v := 0.0
+			if s == current {
+				v = 1
+			}
+			ch <- prometheus.MustNewConstMetric(stateDesc, prometheus.GaugeValue, v, name, s.String())
+		}
+
+		cnt.mu.Lock()
+		ch <- prometheus.MustNewConstMetric(callsDesc, prometheus.CounterValue, cnt.successes, name, "success")
+		ch <- prometheus.MustNewConstMetric(callsDesc, prometheus.CounterValue, cnt.failures, name, "failure")
+		ch <- prometheus.MustNewConstMetric(rejectedDesc, prometheus.CounterValue, cnt.rejected, name)
+		for t, v := range cnt.transitions {
+			ch <- prometheus.MustNewConstMetric(transitionsDesc, prometheus.CounterValue, v, name, t[0].String(), t[1].String())
+		}
+		cnt.mu.Unlock()
+	}
+}
+
+// counter is an observer counting the activity of a single breaker.
+type counter struct {
+	b           *breaker.Breaker
+	mu          sync.Mutex
+	successes   float64
+	failures    float64
+	rejected    float64
+	transitions map[[2]breaker.State]float64
+}
+
+func (c *counter) OnStateChange(from, to breaker.State) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.transitions[[2]breaker.State{from, to}]++
+}
+
+func (c *counter) OnCallRejected() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rejected++
+}
+
+func (c *counter) OnCallCompleted(err error) {}
+
+func (c *counter) OnCallCounted(r breaker.Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch r {
+	case breaker.ResultSuccess:
+		c.successes++
+	case breaker.ResultFailure:
+		c.failures++
+	}
+}
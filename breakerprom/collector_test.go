@@ -0,0 +1,48 @@
+package breakerprom
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/billglover/breaker"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector(t *testing.T) {
+	cb := breaker.NewBreaker().TripAfter(1)
+	c := NewCollector()
+	c.Register("payments", cb)
+
+	reg := prometheus.NewPedanticRegistry()
+	if err := reg.Register(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cb.Protect(func() error { return nil })
+	cb.Protect(func() error { return breaker.Degraded() })
+	cb.Protect(func() error { return errors.New("error") })
+	cb.Protect(func() error { return nil })
+
+	want := `
+# HELP breaker_calls_total Calls completed through the circuit breaker.
+# TYPE breaker_calls_total counter
+breaker_calls_total{name="payments",result="failure"} 1
+breaker_calls_total{name="payments",result="success"} 1
+# HELP breaker_rejected_calls_total Calls rejected by the circuit breaker.
+# TYPE breaker_rejected_calls_total counter
+breaker_rejected_calls_total{name="payments"} 1
+# HELP breaker_state Current state of the circuit breaker.
+# TYPE breaker_state gauge
+breaker_state{name="payments",state="closed"} 0
+breaker_state{name="payments",state="open"} 1
+breaker_state{name="payments",state="partial"} 0
+# HELP breaker_transitions_total State transitions of the circuit breaker.
+# TYPE breaker_transitions_total counter
+breaker_transitions_total{from="closed",name="payments",to="open"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want)); err != nil {
+		t.Fatalf("unexpected metrics: %v", err)
+	}
+}
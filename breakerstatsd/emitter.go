@@ -0,0 +1,117 @@
+/*
+Package breakerstatsd emits the activity of circuit breakers as StatsD
+metrics.
+
+An Emitter observes each breaker registered with it and sends a metric
+for every call outcome and state transition:
+
+	<prefix>.calls            counter, one per counted call, by result
+	<prefix>.rejected         counter, one per call rejected by the breaker
+	<prefix>.transitions      counter, one per state transition
+	<prefix>.state            gauge, 0 closed, 1 partial, 2 open
+
+Calls are reported with the result the breaker counted them as, so slow
+calls are failures, while degraded calls and calls abandoned by the
+caller are not reported.
+
+With DogStatsD tags enabled, the breaker name, result and states are sent
+as tags. Otherwise they are included in the metric name, for example
+<prefix>.payments.calls.success or <prefix>.payments.transitions.closed.open.
+
+	conn, err := net.Dial("udp", "127.0.0.1:8125")
+	e := breakerstatsd.NewEmitter(conn, "myapp.breaker", true)
+	e.Register("payments", payments)
+*/
+package breakerstatsd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/billglover/breaker"
+)
+
+// Emitter sends StatsD metrics for a set of named breakers.
+type Emitter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	prefix string
+	tags   bool
+}
+
+// NewEmitter returns an Emitter writing metrics to w, usually a UDP
+// connection to a StatsD agent. Each metric is sent in a single write.
+// Metric names start with prefix. If tags is true, DogStatsD tags are
+// used.
+func NewEmitter(w io.Writer, prefix string, tags bool) *Emitter {
+	return &Emitter{w: w, prefix: prefix, tags: tags}
+}
+
+// Register starts emitting metrics for the breaker b under name. The
+// current state of the breaker is sent immediately.
+func (e *Emitter) Register(name string, b *breaker.Breaker) {
+	o := &observer{e: e, name: name}
+	b.RegisterObserver(o)
+	e.gauge(name, b.CurrentState())
+}
+
+// send writes a single metric for the breaker name. Labels are given as
+// key value pairs. Errors are ignored since metrics are sent on a best
+// effort basis.
+func (e *Emitter) send(name, metric, value string, labels ...string) {
+	var b strings.Builder
+	if e.tags {
+		fmt.Fprintf(&b, "%s.%s:%s|#breaker:%s", e.prefix, metric, value, name)
+		for i := 0; i+1 < len(labels); i += 2 {
+			fmt.Fprintf(&b, ",%s:%s", labels[i], labels[i+1])
+		}
+	} else {
+		fmt.Fprintf(&b, "%s.%s.%s", e.prefix, name, metric)
+		for i := 0; i+1 < len(labels); i += 2 {
+			fmt.Fprintf(&b, ".%s", labels[i+1])
+		}
+		fmt.Fprintf(&b, ":%s", value)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	io.WriteString(e.w, b.String())
+}
+
+// gauge sends the state gauge for a breaker.
+func (e *Emitter) gauge(name string, s breaker.State) {
+	var v int
+	switch s {
+	case breaker.StatePartial:
+		v = 1
+	case breaker.StateOpen:
+		v = 2
+	}
+	e.send(name, "state", fmt.Sprintf("%d|g", v))
+}
+
+// observer emits metrics for a single breaker.
+type observer struct {
+	e    *Emitter
+	name string
+}
+
+func (o *observer) OnStateChange(from, to breaker.State) {
+	o.e.send(o.name, "transitions", "1|c", "from", from.String(), "to", to.String())
+	o.e.gauge(o.name, to)
+}
+
+func (o *observer) OnCallRejected() {
+	o.e.send(o.name, "rejected", "1|c")
+}
+
+func (o *observer) OnCallCompleted(err error) {}
+
+func (o *observer) OnCallCounted(r breaker.Result) {
+	if r == breaker.ResultDegraded {
+		return
+	}
+	o.e.send(o.name, "calls", "1|c", "result", r.String())
+}
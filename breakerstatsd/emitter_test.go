@@ -0,0 +1,66 @@
+package breakerstatsd
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/billglover/breaker"
+)
+
+// packets records each write as a separate packet.
+type packets struct {
+	mu   sync.Mutex
+	sent []string
+}
+
+func (p *packets) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sent = append(p.sent, string(b))
+	return len(b), nil
+}
+
+func TestEmitterTags(t *testing.T) {
+	p := &packets{}
+	cb := breaker.NewBreaker().TripAfter(1)
+	NewEmitter(p, "app.breaker", true).Register("payments", cb)
+
+	cb.Protect(func() error { return nil })
+	cb.Protect(func() error { return breaker.Degraded() })
+	cb.Protect(func() error { return errors.New("error") })
+	cb.Protect(func() error { return nil })
+
+	want := []string{
+		"app.breaker.state:0|g|#breaker:payments",
+		"app.breaker.calls:1|c|#breaker:payments,result:success",
+		"app.breaker.calls:1|c|#breaker:payments,result:failure",
+		"app.breaker.transitions:1|c|#breaker:payments,from:closed,to:open",
+		"app.breaker.state:2|g|#breaker:payments",
+		"app.breaker.rejected:1|c|#breaker:payments",
+	}
+
+	if slices.Equal(p.sent, want) == false {
+		t.Fatalf("unexpected metrics: want %q, got %q", want, p.sent)
+	}
+}
+
+func TestEmitterNoTags(t *testing.T) {
+	p := &packets{}
+	cb := breaker.NewBreaker().TripAfter(1)
+	NewEmitter(p, "app.breaker", false).Register("payments", cb)
+
+	cb.Protect(func() error { return errors.New("error") })
+
+	want := []string{
+		"app.breaker.payments.state:0|g",
+		"app.breaker.payments.calls.failure:1|c",
+		"app.breaker.payments.transitions.closed.open:1|c",
+		"app.breaker.payments.state:2|g",
+	}
+
+	if slices.Equal(p.sent, want) == false {
+		t.Fatalf("unexpected metrics: want %q, got %q", want, p.sent)
+	}
+}
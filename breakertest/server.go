@@ -0,0 +1,165 @@
+/*
+Package breakertest provides helpers for testing code protected by a
+circuit breaker.
+
+The Server type is a controllable flaky HTTP server. Its failure rate,
+latency and recovery schedule can be configured so that integration
+tests can exercise a breaker configuration and the middleware around it
+against realistic failure modes.
+
+	s := breakertest.NewServer()
+	defer s.Close()
+
+	s.Schedule(
+		breakertest.Phase{Duration: time.Second, FailureRate: 1},
+		breakertest.Phase{FailureRate: 0},
+	)
+*/
+package breakertest
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+)
+
+// Phase describes the behaviour of a Server for a period of time.
+type Phase struct {
+	// Duration is the length of the phase. The final phase of a schedule
+	// lasts indefinitely and its duration is ignored.
+	Duration time.Duration
+
+	// FailureRate is the proportion of requests, between 0 and 1, that
+	// receive a failure response.
+	FailureRate float64
+
+	// Latency is added to every request before it is answered.
+	Latency time.Duration
+}
+
+// Server is an HTTP server that fails a configurable proportion of
+// requests. Successful requests receive a 200 OK response and failed
+// requests receive the configured failure status, 503 Service
+// Unavailable by default.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	rand     *rand.Rand
+	status   int
+	start    time.Time
+	phases   []Phase
+	requests int
+	failures int
+}
+
+// NewServer starts and returns a new Server that answers every request
+// successfully until it is configured otherwise. The caller should call
+// Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		rand:   rand.New(rand.NewPCG(1, 2)),
+		status: http.StatusServiceUnavailable,
+		start:  time.Now(),
+		phases: []Phase{{}},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// SetFailureRate configures the proportion of requests, between 0 and 1,
+// that receive a failure response. It replaces any schedule.
+func (s *Server) SetFailureRate(r float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.phase()
+	p.FailureRate = r
+	s.start, s.phases = time.Now(), []Phase{p}
+}
+
+// SetLatency configures the delay added to every request. It replaces
+// any schedule.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.phase()
+	p.Latency = d
+	s.start, s.phases = time.Now(), []Phase{p}
+}
+
+// SetFailureStatus configures the status code returned by failed
+// requests.
+func (s *Server) SetFailureStatus(code int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = code
+}
+
+// Schedule configures the server to move through the given phases in
+// order, starting immediately. The server remains in the final phase
+// once the schedule is complete, which makes it straightforward to
+// model an outage followed by recovery.
+func (s *Server) Schedule(phases ...Phase) {
+	if len(phases) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.start, s.phases = time.Now(), phases
+}
+
+// Requests returns the number of requests received by the server.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// Failures returns the number of requests that received a failure
+// response.
+func (s *Server) Failures() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.failures
+}
+
+// phase returns the phase the server is currently in
+func (s *Server) phase() Phase {
+	elapsed := time.Since(s.start)
+	for _, p := range s.phases[:len(s.phases)-1] {
+		if elapsed < p.Duration {
+			return p
+		}
+		elapsed -= p.Duration
+	}
+	return s.phases[len(s.phases)-1]
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	p := s.phase()
+	fail := s.rand.Float64() < p.FailureRate
+	status := s.status
+	s.requests++
+	if fail {
+		s.failures++
+	}
+	s.mu.Unlock()
+
+	if p.Latency > 0 {
+		select {
+		case <-time.After(p.Latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if fail {
+		w.WriteHeader(status)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
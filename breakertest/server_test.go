@@ -0,0 +1,81 @@
+package breakertest
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func get(t *testing.T, s *Server) int {
+	t.Helper()
+	resp, err := http.Get(s.URL)
+	if err != nil {
+		t.Fatalf("unexpected response: %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestServerHealthy(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	for i := 0; i < 5; i++ {
+		if code := get(t, s); code != http.StatusOK {
+			t.Fatalf("unexpected status code: want %d, got %d", http.StatusOK, code)
+		}
+	}
+
+	if s.Requests() != 5 {
+		t.Fatalf("unexpected request count: want %d, got %d", 5, s.Requests())
+	}
+}
+
+func TestServerFailureRate(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	s.SetFailureRate(1)
+	s.SetFailureStatus(http.StatusInternalServerError)
+
+	if code := get(t, s); code != http.StatusInternalServerError {
+		t.Fatalf("unexpected status code: want %d, got %d", http.StatusInternalServerError, code)
+	}
+
+	if s.Failures() != 1 {
+		t.Fatalf("unexpected failure count: want %d, got %d", 1, s.Failures())
+	}
+}
+
+func TestServerLatency(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	s.SetLatency(20 * time.Millisecond)
+
+	start := time.Now()
+	get(t, s)
+	if d := time.Since(start); d < 20*time.Millisecond {
+		t.Fatalf("unexpected latency: want at least %v, got %v", 20*time.Millisecond, d)
+	}
+}
+
+func TestServerSchedule(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	s.Schedule(
+		Phase{Duration: 30 * time.Millisecond, FailureRate: 1},
+		Phase{FailureRate: 0},
+	)
+
+	if code := get(t, s); code != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected status code: want %d, got %d", http.StatusServiceUnavailable, code)
+	}
+
+	time.Sleep(40 * time.Millisecond)
+
+	if code := get(t, s); code != http.StatusOK {
+		t.Fatalf("unexpected status code: want %d, got %d", http.StatusOK, code)
+	}
+}
@@ -0,0 +1,33 @@
+package breaker
+
+import "time"
+
+// TripOnBucket configures the breaker to trip using a leaky bucket. Each
+// failed transaction adds a token to a bucket holding up to capacity
+// tokens, and one token drains from the bucket every leak. The breaker
+// trips when a failure overflows the bucket. Unlike TripAfter, a brief
+// burst of failures is tolerated as long as it fits in the bucket, while
+// a sustained failure rate above one per leak eventually trips the
+// breaker however thinly the failures are spread.
+func (b *Breaker) TripOnBucket(capacity int, leak time.Duration) *Breaker {
+	return b.configure(func() {
+		b.setTripPolicy(0, 0, 0, 0, 0)
+		b.bucketCap, b.bucketLeak = capacity, leak
+		b.bucketLevel, b.bucketTime = 0, time.Time{}
+		b.shouldTrip = func() bool {
+			b.drain()
+			return b.bucketLevel > float64(capacity)
+		}
+	})
+}
+
+// drain removes the tokens that have leaked from the bucket since it was
+// last drained. It must be called with the lock held.
+func (b *Breaker) drain() {
+	now := time.Now()
+	if b.bucketLeak > 0 && b.bucketTime.IsZero() == false {
+		leaked := float64(now.Sub(b.bucketTime)) / float64(b.bucketLeak)
+		b.bucketLevel = max(b.bucketLevel-leaked, 0)
+	}
+	b.bucketTime = now
+}
@@ -0,0 +1,64 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTripOnBucket(t *testing.T) {
+	cb := NewBreaker().TripOnBucket(3, time.Hour)
+
+	// a burst that fits in the bucket is tolerated
+	for i := 0; i < 3; i++ {
+		cb.Protect(errorFunc)
+	}
+
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+
+	cb.Protect(errorFunc)
+
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+}
+
+func TestTripOnBucketDrains(t *testing.T) {
+	cb := NewBreaker().TripOnBucket(2, 20*time.Millisecond)
+
+	// failures spread more thinly than the leak rate never trip the breaker
+	for i := 0; i < 5; i++ {
+		cb.Protect(errorFunc)
+		time.Sleep(30 * time.Millisecond)
+	}
+
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+
+	if cb.FailCount() != 5 {
+		t.Fatalf("unexpected fail count: want %d, got %d", 5, cb.FailCount())
+	}
+}
+
+func TestTripOnBucketConfig(t *testing.T) {
+	c := NewBreaker().TripOnBucket(10, time.Second).Config()
+
+	if c.BucketCapacity != 10 || c.BucketLeak != time.Second {
+		t.Fatalf("unexpected bucket policy: want %d per %v, got %d per %v", 10, time.Second, c.BucketCapacity, c.BucketLeak)
+	}
+
+	if c.TripAfter != 0 {
+		t.Fatalf("unexpected trip after: want %d, got %d", 0, c.TripAfter)
+	}
+
+	if problems := c.Lint(); len(problems) != 0 {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+
+	c = NewBreaker().TripOnBucket(10, time.Second).TripAfter(3).Config()
+	if c.BucketCapacity != 0 {
+		t.Fatalf("unexpected bucket capacity: want %d, got %d", 0, c.BucketCapacity)
+	}
+}
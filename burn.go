@@ -0,0 +1,132 @@
+package breaker
+
+import "time"
+
+// burnSlotCount is the number of slots into which the burn rate window
+// is divided. Calls age out of the window one slot at a time.
+const burnSlotCount = 10
+
+// burnMinCalls is the minimum number of calls in the window before a
+// burn rate is reported, so that a single early failure does not raise
+// an alert.
+const burnMinCalls = 10
+
+// Burn identifies the rate reported to a BurnRateObserver.
+type Burn int
+
+// Burn rates
+const (
+	// BurnFailures is the proportion of completed calls that failed.
+	BurnFailures Burn = iota
+
+	// BurnRejections is the proportion of attempted calls that were
+	// rejected by the breaker.
+	BurnRejections
+)
+
+func (r Burn) String() string {
+	switch r {
+	case BurnFailures:
+		return "failures"
+	case BurnRejections:
+		return "rejections"
+	default:
+		return "unknown"
+	}
+}
+
+// BurnRateObserver is an Observer that is also notified when a burn
+// rate configured with BurnRateAlert crosses its threshold. Observers
+// registered with RegisterObserver that implement this interface
+// receive the alerts.
+type BurnRateObserver interface {
+	Observer
+
+	// OnBurnRate is called when rate rises above its threshold, with
+	// alerting set to true, and again with alerting set to false once it
+	// falls back below the threshold.
+	OnBurnRate(r Burn, rate float64, alerting bool)
+}
+
+// BurnRateAlert configures early warning of a degrading dependency.
+// Observers implementing BurnRateObserver are notified when the
+// proportion of calls that fail, or the proportion rejected by the
+// breaker, over the trailing window t rises above the given threshold,
+// and again when it recovers. Unlike a trip policy, an alert changes
+// nothing about how calls are handled, so it can fire well before the
+// breaker trips. Rates are only reported once at least 10 calls have
+// been made in the window. A threshold of zero disables that alert.
+func (b *Breaker) BurnRateAlert(failures, rejections float64, t time.Duration) *Breaker {
+	return b.configure(func() {
+		b.burnFail, b.burnReject, b.burnWindow = failures, rejections, t
+		b.burnSlots = nil
+		b.burning = [2]bool{}
+	})
+}
+
+// burnSlot counts the calls made in one slot of the burn rate window.
+type burnSlot struct {
+	epoch      int64
+	calls      int
+	failures   int
+	rejections int
+}
+
+// burn records a call for the burn rate alerts and notifies observers
+// of any threshold crossed. It must be called with the lock held.
+func (b *Breaker) burn(failed, rejected bool) {
+	if b.burnWindow <= 0 || b.burnFail <= 0 && b.burnReject <= 0 {
+		return
+	}
+	if b.burnSlots == nil {
+		b.burnSlots = make([]burnSlot, burnSlotCount)
+	}
+
+	width := max(b.burnWindow/burnSlotCount, 1)
+	epoch := time.Now().UnixNano() / int64(width)
+	slot := &b.burnSlots[epoch%burnSlotCount]
+	if slot.epoch != epoch {
+		*slot = burnSlot{epoch: epoch}
+	}
+
+	slot.calls++
+	switch {
+	case rejected:
+		slot.rejections++
+	case failed:
+		slot.failures++
+	}
+
+	var calls, failures, rejections int
+	for _, s := range b.burnSlots {
+		if epoch-s.epoch < burnSlotCount {
+			calls += s.calls
+			failures += s.failures
+			rejections += s.rejections
+		}
+	}
+	if calls < burnMinCalls {
+		return
+	}
+
+	if completed := calls - rejections; completed > 0 {
+		b.alert(BurnFailures, float64(failures)/float64(completed), b.burnFail)
+	}
+	b.alert(BurnRejections, float64(rejections)/float64(calls), b.burnReject)
+}
+
+// alert notifies observers if rate has crossed threshold since the last
+// call. It must be called with the lock held.
+func (b *Breaker) alert(r Burn, rate, threshold float64) {
+	if threshold <= 0 || (rate > threshold) == b.burning[r] {
+		return
+	}
+	b.burning[r] = rate > threshold
+
+	alerting := b.burning[r]
+	b.emit(func(o Observer) {
+		if bo, ok := o.(BurnRateObserver); ok {
+			bo.OnBurnRate(r, rate, alerting)
+		}
+	})
+}
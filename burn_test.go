@@ -0,0 +1,87 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+)
+
+// burnRecorder records burn rate alerts
+type burnRecorder struct {
+	recorder
+	alerts []Burn
+	clears []Burn
+}
+
+func (r *burnRecorder) OnBurnRate(b Burn, rate float64, alerting bool) {
+	if alerting {
+		r.alerts = append(r.alerts, b)
+		return
+	}
+	r.clears = append(r.clears, b)
+}
+
+func TestBurnRateAlert(t *testing.T) {
+	cb := NewBreaker().TripAfter(100).BurnRateAlert(0.2, 0.5, time.Minute)
+	r := &burnRecorder{}
+	cb.RegisterObserver(r)
+
+	// no alert is raised before the minimum number of calls
+	for i := 0; i < 5; i++ {
+		cb.Protect(errorFunc)
+	}
+	if len(r.alerts) != 0 {
+		t.Fatalf("unexpected alerts: want none, got %v", r.alerts)
+	}
+
+	for i := 0; i < 5; i++ {
+		cb.Protect(successFunc)
+	}
+	if len(r.alerts) != 1 || r.alerts[0] != BurnFailures {
+		t.Fatalf("unexpected alerts: want %v, got %v", []Burn{BurnFailures}, r.alerts)
+	}
+
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+
+	// the alert is only raised again after the rate has recovered
+	for i := 0; i < 20; i++ {
+		cb.Protect(successFunc)
+	}
+	if len(r.alerts) != 1 || len(r.clears) != 1 || r.clears[0] != BurnFailures {
+		t.Fatalf("unexpected alerts: want %v cleared, got %v raised and %v cleared", BurnFailures, r.alerts, r.clears)
+	}
+}
+
+func TestBurnRateAlertWindow(t *testing.T) {
+	cb := NewBreaker().TripAfter(100).BurnRateAlert(0.2, 0, 50*time.Millisecond)
+	r := &burnRecorder{}
+	cb.RegisterObserver(r)
+
+	for i := 0; i < 5; i++ {
+		cb.Protect(errorFunc)
+	}
+
+	// failures age out of the window
+	time.Sleep(60 * time.Millisecond)
+	for i := 0; i < 10; i++ {
+		cb.Protect(successFunc)
+	}
+	if len(r.alerts) != 0 {
+		t.Fatalf("unexpected alerts: want none, got %v", r.alerts)
+	}
+}
+
+func TestBurns(t *testing.T) {
+	if BurnFailures.String() != "failures" {
+		t.Fatalf("unexpected burn description: want %s, got %s", "failures", BurnFailures.String())
+	}
+
+	if BurnRejections.String() != "rejections" {
+		t.Fatalf("unexpected burn description: want %s, got %s", "rejections", BurnRejections.String())
+	}
+
+	if Burn(30).String() != "unknown" {
+		t.Fatalf("unexpected burn description: want %s, got %s", "unknown", Burn(30).String())
+	}
+}
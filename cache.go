@@ -0,0 +1,132 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// errRefreshPanicked is returned to requests waiting on a refresh that
+// panicked.
+var errRefreshPanicked = errors.New("breaker: cache refresh panicked")
+
+// Cache combines a breaker with a cache of values loaded from the
+// protected system. Values are served from the cache until they are
+// older than the cache's TTL. An expired value is refreshed through the
+// breaker, with concurrent requests for the same key sharing a single
+// refresh. If the refresh fails, or is rejected because the breaker is
+// open, the stale value is served instead. An error is returned only if
+// there is no value to fall back to.
+//
+// Entries are never evicted, so a Cache is best suited to a bounded set
+// of keys such as configuration or reference data.
+type Cache[K comparable, V any] struct {
+	b       *Breaker
+	ttl     time.Duration
+	load    func(ctx context.Context, key K) (V, error)
+	mu      sync.Mutex
+	entries map[K]*cacheEntry[V]
+}
+
+// cacheEntry is a cached value along with any refresh in flight.
+type cacheEntry[V any] struct {
+	value   V
+	ok      bool
+	expires time.Time
+	flight  *cacheFlight[V]
+}
+
+// cacheFlight is a refresh shared by concurrent requests for a key. Its
+// result is available once done is closed.
+type cacheFlight[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// NewCache returns a new cache that calls load, protected by the breaker
+// b, to fetch values that are missing or older than ttl.
+func NewCache[K comparable, V any](b *Breaker, ttl time.Duration, load func(ctx context.Context, key K) (V, error)) *Cache[K, V] {
+	return &Cache[K, V]{
+		b:       b,
+		ttl:     ttl,
+		load:    load,
+		entries: map[K]*cacheEntry[V]{},
+	}
+}
+
+// Get returns the value for key, loading it if it is missing or has
+// expired. If another request is already loading the value, Get waits
+// for it to finish, or for ctx to be done.
+func (c *Cache[K, V]) Get(ctx context.Context, key K) (V, error) {
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if !ok {
+		e = &cacheEntry[V]{}
+		c.entries[key] = e
+	}
+
+	if e.ok && time.Now().Before(e.expires) {
+		c.mu.Unlock()
+		return e.value, nil
+	}
+
+	f := e.flight
+	if f == nil {
+		f = &cacheFlight[V]{done: make(chan struct{})}
+		e.flight = f
+		c.mu.Unlock()
+
+		c.refresh(ctx, key, e, f)
+		return f.value, f.err
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// refresh loads the value for key through the breaker and shares the
+// result with other requests waiting on f.
+func (c *Cache[K, V]) refresh(ctx context.Context, key K, e *cacheEntry[V], f *cacheFlight[V]) {
+	var v V
+	var err error
+	var completed bool
+
+	// the result is shared even if the load panics so that waiting
+	// requests are not left blocked
+	defer func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if completed == false {
+			err = errRefreshPanicked
+		}
+
+		switch {
+		case err == nil:
+			e.value, e.ok, e.expires = v, true, time.Now().Add(c.ttl)
+			f.value = v
+		case e.ok:
+			f.value = e.value
+		default:
+			f.err = err
+		}
+
+		e.flight = nil
+		close(f.done)
+	}()
+
+	err = c.b.ProtectContext(ctx, func(ctx context.Context) error {
+		var err error
+		v, err = c.load(ctx, key)
+		return err
+	})
+	completed = true
+}
@@ -0,0 +1,116 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	var loads atomic.Int64
+	cb := NewBreaker()
+	c := NewCache(cb, time.Minute, func(ctx context.Context, key string) (string, error) {
+		loads.Add(1)
+		return "value-" + key, nil
+	})
+
+	for i := 0; i < 3; i++ {
+		v, err := c.Get(context.Background(), "a")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if v != "value-a" {
+			t.Fatalf("unexpected value: want %q, got %q", "value-a", v)
+		}
+	}
+
+	if loads.Load() != 1 {
+		t.Fatalf("unexpected load count: want %d, got %d", 1, loads.Load())
+	}
+}
+
+func TestCacheSingleRefresh(t *testing.T) {
+	var loads atomic.Int64
+	release := make(chan struct{})
+	cb := NewBreaker()
+	c := NewCache(cb, time.Minute, func(ctx context.Context, key int) (int, error) {
+		loads.Add(1)
+		<-release
+		return 42, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := c.Get(context.Background(), 1); err != nil || v != 42 {
+				t.Errorf("unexpected result: want %d, got %d, %v", 42, v, err)
+			}
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if loads.Load() != 1 {
+		t.Fatalf("unexpected load count: want %d, got %d", 1, loads.Load())
+	}
+}
+
+func TestCacheStale(t *testing.T) {
+	fail := false
+	cb := NewBreaker().TripAfter(1)
+	c := NewCache(cb, 10*time.Millisecond, func(ctx context.Context, key string) (int, error) {
+		if fail {
+			return 0, errors.New("unavailable")
+		}
+		return 1, nil
+	})
+
+	if v, err := c.Get(context.Background(), "a"); err != nil || v != 1 {
+		t.Fatalf("unexpected result: want %d, got %d, %v", 1, v, err)
+	}
+
+	// a failed refresh serves the stale value and trips the breaker
+	fail = true
+	time.Sleep(20 * time.Millisecond)
+	if v, err := c.Get(context.Background(), "a"); err != nil || v != 1 {
+		t.Fatalf("unexpected result: want %d, got %d, %v", 1, v, err)
+	}
+
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+
+	// the stale value is served while the breaker is open
+	if v, err := c.Get(context.Background(), "a"); err != nil || v != 1 {
+		t.Fatalf("unexpected result: want %d, got %d, %v", 1, v, err)
+	}
+
+	// without a stale value the error is returned
+	if _, err := c.Get(context.Background(), "b"); !errors.Is(err, ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+}
+
+func ExampleNewCache() {
+	cb := NewBreaker()
+
+	rates := NewCache(cb, time.Minute, func(ctx context.Context, currency string) (float64, error) {
+		// fetch the exchange rate from the protected system
+		return 1.0, nil
+	})
+
+	rate, err := rates.Get(context.Background(), "EUR")
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	log.Println(rate)
+}
@@ -0,0 +1,30 @@
+package breaker
+
+import (
+	"context"
+	"time"
+)
+
+// Canary calls f every interval, independent of live traffic, and feeds
+// the results into the breaker. It gives a breaker protecting a
+// dependency with little traffic timely evidence that the dependency
+// has failed or recovered. The function f should make a lightweight
+// request to the dependency, such as a health check.
+//
+// Canary calls are made as probes, so a successful call can close an
+// open breaker without waiting for the reset timeout. Canary blocks
+// until ctx is done and then returns the context's error; it is
+// usually run in its own goroutine.
+func (b *Breaker) Canary(ctx context.Context, interval time.Duration, f func(ctx context.Context) error) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			b.ProtectContext(ctx, f, AsProbe())
+		}
+	}
+}
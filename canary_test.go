@@ -0,0 +1,62 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCanary(t *testing.T) {
+	var healthy atomic.Bool
+	cb := NewBreaker().TripAfter(2).ResetAfter(time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- cb.Canary(ctx, 5*time.Millisecond, func(ctx context.Context) error {
+			if healthy.Load() {
+				return nil
+			}
+			return errors.New("unhealthy")
+		})
+	}()
+
+	// failed canary calls trip the breaker without live traffic
+	time.Sleep(50 * time.Millisecond)
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+
+	// a successful canary call closes the breaker before the reset timeout
+	healthy.Store(true)
+	time.Sleep(50 * time.Millisecond)
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected error: want %v, got %v", context.Canceled, err)
+	}
+}
+
+func ExampleBreaker_Canary() {
+	cb := NewBreaker()
+	ctx := context.Background()
+
+	go cb.Canary(ctx, 10*time.Second, func(ctx context.Context) error {
+		// make a lightweight request to the protected system
+		return nil
+	})
+
+	err := cb.Protect(func() error {
+		// make the function call you are trying to protect
+		return nil
+	})
+	if err != nil {
+		log.Println(err)
+	}
+}
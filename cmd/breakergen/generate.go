@@ -0,0 +1,314 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// breakerPath is the import path of the breaker package.
+const breakerPath = "github.com/billglover/breaker"
+
+// method describes a single interface method to be wrapped
+type method struct {
+	name      string
+	params    []string // parameter declarations, e.g. "p0 context.Context"
+	args      []string // arguments used to forward the call, e.g. "p0"
+	results   []string // result types
+	protected bool     // whether the last result is an error
+	context   bool     // whether the first parameter is a context.Context
+}
+
+// generate returns the source of a decorator for the interface named
+// typeName declared in the package in dir.
+func generate(dir, typeName string) ([]byte, error) {
+	fset := token.NewFileSet()
+	files, err := parseDir(fset, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		iface := findInterface(f, typeName)
+		if iface == nil {
+			continue
+		}
+		return render(fset, f, typeName, iface)
+	}
+	return nil, fmt.Errorf("interface %s not found in %s", typeName, dir)
+}
+
+// parseDir parses the non-test Go files in dir
+func parseDir(fset *token.FileSet, dir string) ([]*ast.File, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*ast.File
+	for _, name := range names {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		f, err := parser.ParseFile(fset, name, src, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// findInterface returns the interface type with the given name declared
+// in the file, or nil if there is none.
+func findInterface(f *ast.File, name string) *ast.InterfaceType {
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if ts.Name.Name != name {
+				continue
+			}
+			if it, ok := ts.Type.(*ast.InterfaceType); ok {
+				return it
+			}
+		}
+	}
+	return nil
+}
+
+// render produces the formatted source of the decorator
+func render(fset *token.FileSet, f *ast.File, typeName string, iface *ast.InterfaceType) ([]byte, error) {
+	var methods []method
+	used := map[string]bool{}
+
+	for _, field := range iface.Methods.List {
+		ft, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) == 0 {
+			return nil, fmt.Errorf("interface %s: embedded interfaces are not supported", typeName)
+		}
+		collectPackages(ft, used)
+
+		for _, n := range field.Names {
+			m, err := newMethod(fset, n.Name, ft)
+			if err != nil {
+				return nil, err
+			}
+			methods = append(methods, m)
+		}
+	}
+
+	wrapper := typeName + "Breaker"
+	var buf bytes.Buffer
+	p := func(format string, args ...any) {
+		fmt.Fprintf(&buf, format, args...)
+	}
+
+	p("// Code generated by breakergen -type %s; DO NOT EDIT.\n\n", typeName)
+	p("package %s\n\n", f.Name.Name)
+	p("import (\n")
+	for _, imp := range imports(f, used) {
+		p("\t%s\n", imp)
+	}
+	p("\t%q\n", breakerPath)
+	p(")\n\n")
+
+	p("// %s wraps a %s, protecting each method that returns an error\n", wrapper, typeName)
+	p("// with its own circuit breaker.\n")
+	p("type %s struct {\n", wrapper)
+	p("\tnext     %s\n", typeName)
+	p("\tbreakers map[string]*breaker.Breaker\n\n")
+	p("\t// Classify reports whether an error returned by the named method\n")
+	p("\t// counts as a failure. If nil, every error is a failure.\n")
+	p("\tClassify func(method string, err error) bool\n")
+	p("}\n\n")
+
+	p("// New%s returns a %s forwarding calls to next. The function\n", wrapper, wrapper)
+	p("// newBreaker is called once for each protected method to create its\n")
+	p("// breaker.\n")
+	p("func New%s(next %s, newBreaker func(method string) *breaker.Breaker) *%s {\n", wrapper, typeName, wrapper)
+	p("\treturn &%s{\n", wrapper)
+	p("\t\tnext: next,\n")
+	p("\t\tbreakers: map[string]*breaker.Breaker{\n")
+	for _, m := range methods {
+		if m.protected {
+			p("\t\t\t%q: newBreaker(%q),\n", m.name, m.name)
+		}
+	}
+	p("\t\t},\n")
+	p("\t}\n")
+	p("}\n\n")
+
+	p("// Breaker returns the breaker protecting the named method, or nil if\n")
+	p("// the method is not protected.\n")
+	p("func (w *%s) Breaker(method string) *breaker.Breaker {\n", wrapper)
+	p("\treturn w.breakers[method]\n")
+	p("}\n\n")
+
+	p("// failure reports whether err counts as a failure of the named method\n")
+	p("func (w *%s) failure(method string, err error) bool {\n", wrapper)
+	p("\treturn err != nil && (w.Classify == nil || w.Classify(method, err))\n")
+	p("}\n")
+
+	for _, m := range methods {
+		p("\n")
+		renderMethod(&buf, wrapper, m)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %v\n%s", err, buf.Bytes())
+	}
+	return src, nil
+}
+
+// renderMethod writes the wrapper for a single method
+func renderMethod(buf *bytes.Buffer, wrapper string, m method) {
+	p := func(format string, args ...any) {
+		fmt.Fprintf(buf, format, args...)
+	}
+
+	results := strings.Join(m.results, ", ")
+	if len(m.results) > 1 {
+		results = "(" + results + ")"
+	}
+	call := fmt.Sprintf("w.next.%s(%s)", m.name, strings.Join(m.args, ", "))
+
+	p("// %s calls the underlying %s", m.name, m.name)
+	if m.protected {
+		p(" through its breaker.\n")
+	} else {
+		p(". It is not protected since it does not\n// return an error.\n")
+	}
+	p("func (w *%s) %s(%s) %s {\n", wrapper, m.name, strings.Join(m.params, ", "), results)
+
+	if !m.protected {
+		if len(m.results) == 0 {
+			p("\t%s\n", call)
+		} else {
+			p("\treturn %s\n", call)
+		}
+		p("}\n")
+		return
+	}
+
+	var vars []string
+	for i, r := range m.results[:len(m.results)-1] {
+		v := fmt.Sprintf("r%d", i)
+		vars = append(vars, v)
+		p("\tvar %s %s\n", v, r)
+	}
+	p("\tvar callErr error\n")
+	if m.context {
+		// pass the context through the breaker so that cancellation is
+		// not counted as a failure
+		args := append([]string{"ctx"}, m.args[1:]...)
+		call = fmt.Sprintf("w.next.%s(%s)", m.name, strings.Join(args, ", "))
+		p("\terr := w.breakers[%q].ProtectContext(p0, func(ctx context.Context) error {\n", m.name)
+	} else {
+		p("\terr := w.breakers[%q].Protect(func() error {\n", m.name)
+	}
+	p("\t\t%s = %s\n", strings.Join(append(vars, "callErr"), ", "), call)
+	p("\t\tif !w.failure(%q, callErr) {\n", m.name)
+	p("\t\t\treturn nil\n")
+	p("\t\t}\n")
+	p("\t\treturn callErr\n")
+	p("\t})\n")
+	p("\tif err == nil {\n")
+	p("\t\terr = callErr\n")
+	p("\t}\n")
+	p("\treturn %s\n", strings.Join(append(vars, "err"), ", "))
+	p("}\n")
+}
+
+// newMethod describes a method with the given name and signature
+func newMethod(fset *token.FileSet, name string, ft *ast.FuncType) (method, error) {
+	m := method{name: name}
+
+	i := 0
+	for _, field := range ft.Params.List {
+		typ := exprString(fset, field.Type)
+		count := max(len(field.Names), 1)
+		for range count {
+			v := fmt.Sprintf("p%d", i)
+			i++
+			m.params = append(m.params, v+" "+typ)
+			if _, ok := field.Type.(*ast.Ellipsis); ok {
+				v += "..."
+			}
+			m.args = append(m.args, v)
+		}
+	}
+
+	if ft.Results != nil {
+		for _, field := range ft.Results.List {
+			typ := exprString(fset, field.Type)
+			for range max(len(field.Names), 1) {
+				m.results = append(m.results, typ)
+			}
+		}
+	}
+
+	m.protected = len(m.results) > 0 && m.results[len(m.results)-1] == "error"
+	m.context = len(m.params) > 0 && strings.HasSuffix(m.params[0], " context.Context")
+	return m, nil
+}
+
+// exprString returns the source representation of an expression
+func exprString(fset *token.FileSet, e ast.Expr) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, fset, e)
+	return buf.String()
+}
+
+// collectPackages records the package names referenced by a signature
+func collectPackages(n ast.Node, used map[string]bool) {
+	ast.Inspect(n, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok {
+				used[id.Name] = true
+			}
+		}
+		return true
+	})
+}
+
+// imports returns the import specs from the file that are referenced by
+// the wrapped methods
+func imports(f *ast.File, used map[string]bool) []string {
+	var specs []string
+	for _, imp := range f.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		if !used[name] || path == breakerPath {
+			continue
+		}
+
+		spec := strconv.Quote(path)
+		if imp.Name != nil {
+			spec = imp.Name.Name + " " + spec
+		}
+		specs = append(specs, spec)
+	}
+	sort.Strings(specs)
+	return specs
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const source = `package store
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Store is a client for a remote store.
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Put(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Keys(prefix string, limit ...int) []string
+	Close()
+	Ping() error
+}
+
+var _ io.Reader
+`
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "store.go"), []byte(source), 0o644); err != nil {
+		t.Fatalf("unexpected error writing source: %v", err)
+	}
+
+	src, err := generate(dir, "Store")
+	if err != nil {
+		t.Fatalf("unexpected response: %v", err)
+	}
+
+	f, err := parser.ParseFile(token.NewFileSet(), "store_breaker.go", src, 0)
+	if err != nil {
+		t.Fatalf("unexpected error parsing generated code: %v\n%s", err, src)
+	}
+
+	var imports []string
+	for _, imp := range f.Imports {
+		imports = append(imports, imp.Path.Value)
+	}
+	want := `["context" "github.com/billglover/breaker" "time"]`
+	if got := strings.Join([]string{"[", strings.Join(imports, " "), "]"}, ""); got != want {
+		t.Fatalf("unexpected imports: want %s, got %s", want, got)
+	}
+
+	for _, s := range []string{
+		"type StoreBreaker struct",
+		"func NewStoreBreaker(next Store, newBreaker func(method string) *breaker.Breaker) *StoreBreaker",
+		"func (w *StoreBreaker) Get(p0 context.Context, p1 string) ([]byte, error)",
+		`w.breakers["Put"].ProtectContext(p0, func(ctx context.Context) error {`,
+		"callErr = w.next.Put(ctx, p1, p2, p3)",
+		"return w.next.Keys(p0, p1...)",
+		"w.next.Close()",
+		`w.breakers["Ping"].Protect(func() error {`,
+	} {
+		if !strings.Contains(string(src), s) {
+			t.Fatalf("generated code does not contain %q:\n%s", s, src)
+		}
+	}
+
+	if strings.Contains(string(src), `"Keys": newBreaker`) {
+		t.Fatalf("unexpected breaker for method without an error result:\n%s", src)
+	}
+}
+
+func TestGenerateMissingType(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "store.go"), []byte(source), 0o644); err != nil {
+		t.Fatalf("unexpected error writing source: %v", err)
+	}
+
+	_, err := generate(dir, "Missing")
+	if err == nil {
+		t.Fatalf("unexpected response: no error returned")
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Breakergen generates a decorator that protects each method of an
+interface with its own circuit breaker.
+
+Given an interface declared in the current package, breakergen writes a
+type implementing the same interface that forwards every call to an
+underlying implementation through a per-method breaker. Methods whose
+last result is an error are protected; other methods are forwarded
+unchanged. Methods taking a context.Context as their first parameter
+are called through ProtectContext. A classification hook decides which
+errors count as failures.
+
+Typical use is through go:generate:
+
+	//go:generate breakergen -type Client
+
+Usage:
+
+	breakergen -type T [-output file] [-dir directory]
+
+The generated type is named TBreaker and is created with NewTBreaker.
+By default it is written to t_breaker.go in the package directory.
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("breakergen: ")
+
+	typeName := flag.String("type", "", "name of the interface to wrap; required")
+	output := flag.String("output", "", "output file name; default <type>_breaker.go")
+	dir := flag.String("dir", ".", "directory of the package declaring the interface")
+	flag.Parse()
+
+	if *typeName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	src, err := generate(*dir, *typeName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	name := *output
+	if name == "" {
+		name = fmt.Sprintf("%s_breaker.go", strings.ToLower(*typeName))
+	}
+	if !filepath.IsAbs(name) {
+		name = filepath.Join(*dir, name)
+	}
+
+	if err := os.WriteFile(name, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,97 @@
+package breaker
+
+import (
+	"fmt"
+	"time"
+)
+
+// ErrColdStart matches the error returned when a call is rejected
+// because the breaker has recently closed and the call would exceed the
+// admitted rate set by ColdStart. The error is an *OpenError with
+// ColdStart set, so callers already handling ErrOpen treat it the same
+// way.
+var ErrColdStart = fmt.Errorf("breaker: call exceeds cold start rate: %w", ErrOpen)
+
+// ColdStart limits the rate of calls admitted after the breaker closes,
+// for dependencies that autoscale from zero and need time to add
+// capacity after an outage. When the breaker closes, calls are admitted
+// at initial calls per second, a ceiling that grows steadily to reach
+// ceiling calls per second once ramp has elapsed, after which the limit
+// is lifted. Calls over the limit are rejected with an *OpenError
+// matching ErrColdStart.
+//
+// The limit is enforced with a token bucket holding at most one second
+// of calls at the current rate, so short bursts are smoothed rather than
+// rejected outright. A ramp of zero disables cold start protection.
+func (b *Breaker) ColdStart(initial, ceiling float64, ramp time.Duration) *Breaker {
+	return b.configure(func() {
+		b.rampFrom, b.rampTo, b.rampDur = initial, ceiling, ramp
+		b.rampStart = time.Time{}
+	})
+}
+
+// startRamp begins limiting the rate of admitted calls. It must be
+// called with the lock held.
+func (b *Breaker) startRamp() {
+	if b.rampDur <= 0 {
+		return
+	}
+	b.rampStart = time.Now()
+	b.rampTime = b.rampStart
+	b.rampTokens = 1
+}
+
+// ramp returns the rate of calls allowed at now while ramping up after
+// closing and the tokens that would then be available, without taking
+// any. It reports false if the breaker is not ramping up. It must be
+// called with the lock held.
+func (b *Breaker) ramp(now time.Time) (rate, tokens float64, ok bool) {
+	if b.rampStart.IsZero() {
+		return 0, 0, false
+	}
+
+	elapsed := now.Sub(b.rampStart)
+	if elapsed >= b.rampDur {
+		return 0, 0, false
+	}
+
+	rate = b.rampFrom + (b.rampTo-b.rampFrom)*float64(elapsed)/float64(b.rampDur)
+	tokens = min(b.rampTokens+rate*now.Sub(b.rampTime).Seconds(), max(rate, 1))
+	return rate, tokens, true
+}
+
+// rampWait returns how long it will be until the next call is admitted
+// while ramping up after closing, or zero if one would be admitted now.
+// It must be called with the lock held.
+func (b *Breaker) rampWait() time.Duration {
+	now := time.Now()
+	rate, tokens, ok := b.ramp(now)
+	switch {
+	case ok == false || tokens >= 1:
+		return 0
+	case rate <= 0:
+		return b.rampDur - now.Sub(b.rampStart)
+	default:
+		return time.Duration((1 - tokens) / rate * float64(time.Second))
+	}
+}
+
+// coldStarting reports whether admitting n calls would exceed the rate
+// allowed while ramping up after closing, taking the tokens for them if
+// not. It must be called with the lock held.
+func (b *Breaker) coldStarting(n int) bool {
+	now := time.Now()
+	_, tokens, ok := b.ramp(now)
+	if ok == false {
+		b.rampStart = time.Time{}
+		return false
+	}
+	b.rampTokens = tokens
+	b.rampTime = now
+
+	if b.rampTokens < float64(n) {
+		return true
+	}
+	b.rampTokens -= float64(n)
+	return false
+}
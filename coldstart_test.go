@@ -0,0 +1,103 @@
+package breaker
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestColdStart(t *testing.T) {
+	cb := NewBreaker().TripAfter(1).ColdStart(1, 1000, 100*time.Millisecond)
+
+	// calls are not limited before the breaker has closed after a trip
+	for i := 0; i < 5; i++ {
+		if err := cb.Protect(successFunc); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	cb.Open()
+	cb.Close()
+
+	if err := cb.Protect(successFunc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := cb.Protect(successFunc)
+	if errors.Is(err, ErrColdStart) == false || errors.Is(err, ErrOpen) == false {
+		t.Fatalf("unexpected error: want %v, got %v", ErrColdStart, err)
+	}
+	var oe *OpenError
+	if errors.As(err, &oe) == false || oe.ColdStart == false || oe.State != StateClosed {
+		t.Fatalf("unexpected error: want cold start *OpenError, got %v", err)
+	}
+	if oe.RetryAfter <= 0 {
+		t.Fatalf("unexpected retry after: want > 0, got %v", oe.RetryAfter)
+	}
+
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+
+	if p := cb.AdmissionProbability(); p >= 1 {
+		t.Fatalf("unexpected admission probability: want < 1, got %v", p)
+	}
+	if cb.RetryAfter() <= 0 {
+		t.Fatalf("unexpected retry after: want > 0, got %v", cb.RetryAfter())
+	}
+	if ra := RetryAfterHeader(cb); ra != "1" {
+		t.Fatalf("unexpected retry after header: want %q, got %q", "1", ra)
+	}
+
+	// the limit is lifted once the ramp has elapsed
+	time.Sleep(110 * time.Millisecond)
+	if p := cb.AdmissionProbability(); p != 1 {
+		t.Fatalf("unexpected admission probability: want %v, got %v", 1, p)
+	}
+	if ra := RetryAfterHeader(cb); ra != "" {
+		t.Fatalf("unexpected retry after header: want %q, got %q", "", ra)
+	}
+	for i := 0; i < 5; i++ {
+		if err := cb.Protect(successFunc); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestColdStartHandler(t *testing.T) {
+	cb := NewBreaker().ColdStart(1, 1, time.Minute)
+	cb.Open()
+	cb.Close()
+
+	h := NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), cb)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected status: want %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+	if ra := w.Header().Get("Retry-After"); ra != "1" {
+		t.Fatalf("unexpected retry after header: want %q, got %q", "1", ra)
+	}
+}
+
+func TestColdStartConfig(t *testing.T) {
+	c := NewBreaker().ColdStart(10, 100, time.Minute).Config()
+
+	if c.ColdStartRate != 10 || c.ColdStartCeiling != 100 || c.ColdStartRamp != time.Minute {
+		t.Fatalf("unexpected cold start: want %v to %v over %v, got %v to %v over %v",
+			10, 100, time.Minute, c.ColdStartRate, c.ColdStartCeiling, c.ColdStartRamp)
+	}
+
+	if problems := c.Lint(); len(problems) != 0 {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+
+	c = NewBreaker().ColdStart(100, 10, time.Minute).Config()
+	if problems := c.Lint(); len(problems) != 1 {
+		t.Fatalf("unexpected problem count: want %d, got %d: %v", 1, len(problems), problems)
+	}
+}
@@ -0,0 +1,291 @@
+package breaker
+
+import (
+	"fmt"
+	"time"
+)
+
+// Config describes the effective configuration of a breaker. Settings
+// that have not been enabled hold their zero value.
+type Config struct {
+	// TripAfter is the number of failures after which the breaker trips,
+	// as configured by TripAfter.
+	TripAfter int `json:"trip_after,omitempty"`
+
+	// WindowSize is the number of recent transactions counted, as
+	// configured by SlidingWindow.
+	WindowSize int `json:"window_size,omitempty"`
+
+	// MaxFailureAge is the age after which failures no longer count
+	// towards tripping the breaker.
+	MaxFailureAge time.Duration `json:"max_failure_age,omitempty"`
+
+	// SuccessRate and SuccessRatePeriod hold the policy configured by
+	// TripOnSuccessRate.
+	SuccessRate       float64       `json:"success_rate,omitempty"`
+	SuccessRatePeriod time.Duration `json:"success_rate_period,omitempty"`
+
+	// FailureRate and MinRequests hold the policy configured by
+	// TripOnRate.
+	FailureRate float64 `json:"failure_rate,omitempty"`
+	MinRequests int     `json:"min_requests,omitempty"`
+
+	// BucketCapacity and BucketLeak hold the policy configured by
+	// TripOnBucket.
+	BucketCapacity int           `json:"bucket_capacity,omitempty"`
+	BucketLeak     time.Duration `json:"bucket_leak,omitempty"`
+
+	// SlowCallThreshold is the duration after which a successful call is
+	// counted as a failure. Zero means slow calls are not detected.
+	SlowCallThreshold time.Duration `json:"slow_call_threshold,omitempty"`
+
+	// LatencyWindow is the age after which call latencies are no longer
+	// used by Recommend. Zero means latencies are kept regardless of age.
+	LatencyWindow time.Duration `json:"latency_window,omitempty"`
+
+	// ColdStartRate, ColdStartCeiling and ColdStartRamp hold the rate
+	// limit configured by ColdStart.
+	ColdStartRate    float64       `json:"cold_start_rate,omitempty"`
+	ColdStartCeiling float64       `json:"cold_start_ceiling,omitempty"`
+	ColdStartRamp    time.Duration `json:"cold_start_ramp,omitempty"`
+
+	// BurnFailureRate, BurnRejectionRate and BurnWindow hold the alert
+	// thresholds configured by BurnRateAlert.
+	BurnFailureRate   float64       `json:"burn_failure_rate,omitempty"`
+	BurnRejectionRate float64       `json:"burn_rejection_rate,omitempty"`
+	BurnWindow        time.Duration `json:"burn_window,omitempty"`
+
+	// ResetAfter is the time after which an open breaker allows a probe
+	// call, measured from the event given by ResetTimerFrom.
+	ResetAfter     time.Duration `json:"reset_after"`
+	ResetTimerFrom Anchor        `json:"reset_timer_from"`
+
+	// ResetBackoff is the maximum reset timeout reached by doubling the
+	// timeout each time the breaker trips from the partially open state.
+	// Zero means the timeout does not grow.
+	ResetBackoff time.Duration `json:"reset_backoff,omitempty"`
+
+	// ResetJitter is the maximum fraction by which the reset timeout is
+	// randomly extended.
+	ResetJitter float64 `json:"reset_jitter,omitempty"`
+
+	// CloseAfter is the number of probes that must complete before a
+	// successful probe closes the breaker from the partially open state.
+	CloseAfter int `json:"close_after"`
+
+	// ReopenAfter is the number of failed probes that trip the breaker
+	// again from the partially open state.
+	ReopenAfter int `json:"reopen_after"`
+
+	// MaxProbes is the maximum number of concurrent calls allowed in the
+	// partially open state. Zero means no limit.
+	MaxProbes int `json:"max_probes,omitempty"`
+
+	// RecoverPanics reports whether panics in the protected function are
+	// returned as errors rather than raised again.
+	RecoverPanics bool `json:"recover_panics"`
+
+	// FlappingTrips, FlappingPeriod and FlappingDampening hold the
+	// flapping detection settings.
+	FlappingTrips     int           `json:"flapping_trips,omitempty"`
+	FlappingPeriod    time.Duration `json:"flapping_period,omitempty"`
+	FlappingDampening time.Duration `json:"flapping_dampening,omitempty"`
+
+	// ObserverTimeout and ObserverSampleRate control the delivery of
+	// notifications to observers.
+	ObserverTimeout    time.Duration `json:"observer_timeout,omitempty"`
+	ObserverSampleRate float64       `json:"observer_sample_rate"`
+}
+
+// Config returns the effective configuration of the breaker, including
+// any defaults and changes made since the breaker was created.
+func (b *Breaker) Config() Config {
+	b.lock()
+	defer b.mu.Unlock()
+	return b.config()
+}
+
+// Lint checks the configuration for settings that are valid but likely
+// to be mistakes, returning a description of each problem found and how
+// to address it. An empty result means no problems were found. Lint is
+// intended to be called at startup or from tests run in CI.
+func (c Config) Lint() []string {
+	var problems []string
+	add := func(format string, a ...any) {
+		problems = append(problems, fmt.Sprintf(format, a...))
+	}
+
+	if c.SuccessRate == 0 && c.FailureRate == 0 && c.BucketCapacity == 0 && c.TripAfter <= 0 {
+		add("TripAfter is %d so the breaker trips on the first call; use a positive value", c.TripAfter)
+	}
+
+	if c.SuccessRate < 0 || c.SuccessRate > 1 {
+		add("SuccessRate %v is outside the range 0 to 1; use a proportion such as 0.9", c.SuccessRate)
+	}
+
+	if c.SuccessRate > 0 && c.SuccessRatePeriod <= 0 {
+		add("SuccessRatePeriod is %v so the success rate is measured over single calls; use a period of at least several seconds", c.SuccessRatePeriod)
+	}
+
+	if c.FailureRate < 0 || c.FailureRate > 1 {
+		add("FailureRate %v is outside the range 0 to 1; use a proportion such as 0.5", c.FailureRate)
+	}
+
+	if c.FailureRate > 0 && c.MinRequests < 10 {
+		add("MinRequests is %d so a few early failures can trip the breaker; use a value of at least 10", c.MinRequests)
+	}
+
+	if c.BucketCapacity > 0 && c.BucketLeak <= 0 {
+		add("BucketLeak is %v so failures never drain from the bucket; use a positive duration", c.BucketLeak)
+	}
+
+	if c.WindowSize > 0 && c.TripAfter > c.WindowSize {
+		add("TripAfter %d is larger than WindowSize %d so the breaker never trips; use a larger window", c.TripAfter, c.WindowSize)
+	}
+
+	if c.WindowSize > 0 && c.FailureRate > 0 && c.MinRequests > c.WindowSize {
+		add("MinRequests %d is larger than WindowSize %d so the failure rate is never evaluated; use a larger window", c.MinRequests, c.WindowSize)
+	}
+
+	if c.SlowCallThreshold < 0 {
+		add("SlowCallThreshold %v is negative; use zero to disable slow call detection", c.SlowCallThreshold)
+	}
+
+	if c.LatencyWindow < 0 {
+		add("LatencyWindow %v is negative; use zero to keep latencies regardless of age", c.LatencyWindow)
+	}
+
+	if c.ColdStartRamp > 0 && c.ColdStartRate <= 0 {
+		add("ColdStartRate is %v so no calls are admitted after closing until the ramp ends; use a positive rate", c.ColdStartRate)
+	}
+
+	if c.ColdStartRamp > 0 && c.ColdStartCeiling < c.ColdStartRate {
+		add("ColdStartCeiling %v is lower than ColdStartRate %v so the admitted rate falls during the ramp; use a higher ceiling", c.ColdStartCeiling, c.ColdStartRate)
+	}
+
+	if c.BurnFailureRate < 0 || c.BurnFailureRate > 1 {
+		add("BurnFailureRate %v is outside the range 0 to 1; use a proportion such as 0.1", c.BurnFailureRate)
+	}
+
+	if c.BurnRejectionRate < 0 || c.BurnRejectionRate > 1 {
+		add("BurnRejectionRate %v is outside the range 0 to 1; use a proportion such as 0.1", c.BurnRejectionRate)
+	}
+
+	if (c.BurnFailureRate > 0 || c.BurnRejectionRate > 0) && c.BurnWindow <= 0 {
+		add("BurnWindow is %v so burn rate alerts never fire; use a window of at least several seconds", c.BurnWindow)
+	}
+
+	if c.ResetAfter <= 0 {
+		add("ResetAfter is %v so an open breaker allows calls through immediately; use a positive duration", c.ResetAfter)
+	}
+
+	if c.ResetBackoff > 0 && c.ResetBackoff < c.ResetAfter {
+		add("ResetBackoff %v is shorter than ResetAfter %v so the timeout never grows; use a longer maximum", c.ResetBackoff, c.ResetAfter)
+	}
+
+	if c.ResetJitter < 0 || c.ResetJitter > 1 {
+		add("ResetJitter %v is outside the range 0 to 1; use a fraction such as 0.2", c.ResetJitter)
+	}
+
+	if c.CloseAfter < 1 {
+		add("CloseAfter is %d; use a value of at least 1", c.CloseAfter)
+	}
+
+	if c.ReopenAfter < 1 {
+		add("ReopenAfter is %d so the breaker trips again before any probe completes; use a value of at least 1", c.ReopenAfter)
+	}
+
+	if c.ReopenAfter > c.CloseAfter {
+		add("ReopenAfter %d is larger than CloseAfter %d so a successful probe can close the breaker before that many probes have failed; use a value no larger than CloseAfter", c.ReopenAfter, c.CloseAfter)
+	}
+
+	if c.FlappingTrips == 1 {
+		add("FlappingTrips is 1 so every trip is reported as flapping; use a value of at least 2")
+	}
+
+	if c.FlappingTrips > 1 && c.FlappingPeriod <= c.ResetAfter*time.Duration(c.FlappingTrips-1) {
+		add("FlappingPeriod %v cannot contain %d trips with ResetAfter %v so flapping is never detected; use a period longer than %v",
+			c.FlappingPeriod, c.FlappingTrips, c.ResetAfter, c.ResetAfter*time.Duration(c.FlappingTrips-1))
+	}
+
+	if c.FlappingDampening > 0 && c.FlappingTrips == 0 {
+		add("FlappingDampening is set but flapping detection is disabled; configure DetectFlapping")
+	}
+
+	if c.ObserverSampleRate < 0 || c.ObserverSampleRate > 1 {
+		add("ObserverSampleRate %v is outside the range 0 to 1; use a proportion such as 0.1", c.ObserverSampleRate)
+	}
+
+	if c.ObserverTimeout < 0 {
+		add("ObserverTimeout %v is negative; use zero to disable the timeout", c.ObserverTimeout)
+	}
+
+	return problems
+}
+
+// ConfigObserver is an Observer that is also notified when the
+// configuration of the breaker is changed at runtime. Observers
+// registered with RegisterObserver that implement this interface receive
+// the previous and new effective configuration.
+type ConfigObserver interface {
+	Observer
+	OnConfigChange(old, new Config)
+}
+
+// config returns the effective configuration. It must be called with
+// the lock held.
+func (b *Breaker) config() Config {
+	return Config{
+		TripAfter:          b.tripAfter,
+		WindowSize:         len(b.window),
+		MaxFailureAge:      b.maxFailAge,
+		SuccessRate:        b.tripRate,
+		SuccessRatePeriod:  b.tripPeriod,
+		FailureRate:        b.failRate,
+		MinRequests:        b.minRequests,
+		BucketCapacity:     b.bucketCap,
+		BucketLeak:         b.bucketLeak,
+		SlowCallThreshold:  b.slowCall,
+		LatencyWindow:      b.latencyWindow,
+		ColdStartRate:      b.rampFrom,
+		ColdStartCeiling:   b.rampTo,
+		ColdStartRamp:      b.rampDur,
+		BurnFailureRate:    b.burnFail,
+		BurnRejectionRate:  b.burnReject,
+		BurnWindow:         b.burnWindow,
+		ResetAfter:         b.resetAfter,
+		ResetTimerFrom:     b.anchor,
+		ResetBackoff:       b.backoffMax,
+		ResetJitter:        b.jitter,
+		CloseAfter:         b.closeAfter,
+		ReopenAfter:        b.reopenAfter,
+		MaxProbes:          b.maxProbes,
+		RecoverPanics:      b.recoverPanics,
+		FlappingTrips:      b.flapLimit,
+		FlappingPeriod:     b.flapWindow,
+		FlappingDampening:  b.flapDampen,
+		ObserverTimeout:    b.obsTimeout,
+		ObserverSampleRate: 1 - b.obsSkip,
+	}
+}
+
+// configure applies a change to the configuration of the breaker and
+// notifies observers implementing ConfigObserver if the effective
+// configuration has changed.
+func (b *Breaker) configure(f func()) *Breaker {
+	b.lock()
+	defer b.unlock()
+
+	old := b.config()
+	f()
+
+	cfg := b.config()
+	if cfg != old {
+		b.emit(func(o Observer) {
+			if co, ok := o.(ConfigObserver); ok {
+				co.OnConfigChange(old, cfg)
+			}
+		})
+	}
+	return b
+}
@@ -0,0 +1,101 @@
+package breaker
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestConfigDefaults(t *testing.T) {
+	c := NewBreaker().Config()
+
+	if c.TripAfter != 5 {
+		t.Fatalf("unexpected trip after: want %d, got %d", 5, c.TripAfter)
+	}
+
+	if c.ResetAfter != 50*time.Millisecond {
+		t.Fatalf("unexpected reset after: want %v, got %v", 50*time.Millisecond, c.ResetAfter)
+	}
+
+	if c.CloseAfter != 1 || c.ReopenAfter != 1 {
+		t.Fatalf("unexpected probe policy: want %d of %d, got %d of %d", 1, 1, c.ReopenAfter, c.CloseAfter)
+	}
+
+	if c.ObserverSampleRate != 1 {
+		t.Fatalf("unexpected observer sample rate: want %v, got %v", 1.0, c.ObserverSampleRate)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	cb := NewBreaker().TripOnSuccessRate(0.9, time.Minute).ResetTimerFrom(AnchorTrip)
+	c := cb.Config()
+
+	if c.TripAfter != 0 {
+		t.Fatalf("unexpected trip after: want %d, got %d", 0, c.TripAfter)
+	}
+
+	if c.SuccessRate != 0.9 || c.SuccessRatePeriod != time.Minute {
+		t.Fatalf("unexpected success rate policy: want %v over %v, got %v over %v", 0.9, time.Minute, c.SuccessRate, c.SuccessRatePeriod)
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatalf("unexpected response: %v", err)
+	}
+
+	var m map[string]any
+	json.Unmarshal(b, &m)
+	if m["reset_timer_from"] != "trip" {
+		t.Fatalf("unexpected serialised anchor: want %s, got %v", "trip", m["reset_timer_from"])
+	}
+}
+
+// configRecorder records configuration changes
+type configRecorder struct {
+	recorder
+	changes [][2]Config
+}
+
+func (r *configRecorder) OnConfigChange(old, new Config) {
+	r.changes = append(r.changes, [2]Config{old, new})
+}
+
+func TestConfigChange(t *testing.T) {
+	cb := NewBreaker()
+	r := &configRecorder{}
+	cb.RegisterObserver(r)
+
+	cb.TripAfter(10)
+
+	// unchanged configuration should not be reported
+	cb.TripAfter(10)
+
+	if len(r.changes) != 1 {
+		t.Fatalf("unexpected configuration change count: want %d, got %d", 1, len(r.changes))
+	}
+
+	if r.changes[0][0].TripAfter != 5 || r.changes[0][1].TripAfter != 10 {
+		t.Fatalf("unexpected configuration change: want %d to %d, got %d to %d", 5, 10, r.changes[0][0].TripAfter, r.changes[0][1].TripAfter)
+	}
+}
+
+func TestLintDefaults(t *testing.T) {
+	problems := NewBreaker().Config().Lint()
+
+	if len(problems) != 0 {
+		t.Fatalf("unexpected problems with default configuration: %v", problems)
+	}
+}
+
+func TestLint(t *testing.T) {
+	c := NewBreaker().
+		ResetAfter(time.Second).
+		DetectFlapping(3, time.Second).
+		SampleObservations(2).
+		Config()
+
+	problems := c.Lint()
+	if len(problems) != 2 {
+		t.Fatalf("unexpected problem count: want %d, got %d: %v", 2, len(problems), problems)
+	}
+}
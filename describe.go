@@ -0,0 +1,31 @@
+package breaker
+
+// Describe registers a user-facing description for a state, for example
+// "payments provider temporarily unavailable". Calls rejected while the
+// breaker is in that state return an error whose message is the
+// description; errors.Is(err, ErrOpen) continues to report true. An
+// empty description removes a previously registered one.
+func (b *Breaker) Describe(s State, description string) *Breaker {
+	return b.configure(func() {
+		if description == "" {
+			delete(b.descriptions, s)
+			return
+		}
+		if b.descriptions == nil {
+			b.descriptions = make(map[State]string)
+		}
+		b.descriptions[s] = description
+	})
+}
+
+// Description returns the description registered for a state, or the
+// name of the state if no description has been registered.
+func (b *Breaker) Description(s State) string {
+	b.lock()
+	defer b.mu.Unlock()
+
+	if d, ok := b.descriptions[s]; ok {
+		return d
+	}
+	return s.String()
+}
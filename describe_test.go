@@ -0,0 +1,56 @@
+package breaker
+
+import (
+	"errors"
+	"log"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	desc := "payments provider temporarily unavailable"
+	cb := NewBreaker().Describe(StateOpen, desc)
+
+	if d := cb.Description(StateOpen); d != desc {
+		t.Fatalf("unexpected description: want %q, got %q", desc, d)
+	}
+
+	if d := cb.Description(StateClosed); d != "closed" {
+		t.Fatalf("unexpected description: want %q, got %q", "closed", d)
+	}
+
+	cb.Open()
+	err := cb.Protect(successFunc)
+	if !errors.Is(err, ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+	if err.Error() != desc {
+		t.Fatalf("unexpected error message: want %q, got %q", desc, err.Error())
+	}
+}
+
+func TestDescribeRemove(t *testing.T) {
+	cb := NewBreaker().Describe(StateOpen, "unavailable").Describe(StateOpen, "")
+
+	if d := cb.Description(StateOpen); d != "open" {
+		t.Fatalf("unexpected description: want %q, got %q", "open", d)
+	}
+
+	cb.Open()
+	if err := cb.Protect(successFunc); err.Error() != ErrOpen.Error() {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+}
+
+func ExampleBreaker_Describe() {
+	cb := NewBreaker().Describe(StateOpen, "payments provider temporarily unavailable")
+
+	err := cb.Protect(func() error {
+		// make the function call you are trying to protect
+		return nil
+	})
+
+	if errors.Is(err, ErrOpen) {
+		// the error message is suitable for showing to a user
+		log.Println(err)
+	}
+}
@@ -0,0 +1,45 @@
+package breaker
+
+import (
+	"context"
+	"net"
+)
+
+// ContextDialer is implemented by *net.Dialer and *tls.Dialer.
+type ContextDialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// Dialer protects outbound dials with a breaker. Failed dials, such as
+// refused connections and dial timeouts, are counted as failures and
+// further dials fail immediately with ErrOpen while the breaker is open.
+// Errors on established connections are not counted. A Dialer is
+// typically used for a single target, with its own breaker.
+type Dialer struct {
+	d ContextDialer
+	b *Breaker
+}
+
+// NewDialer returns a Dialer that dials using d, protected by the
+// breaker b. Passing a *tls.Dialer protects TLS handshakes as well as
+// connection establishment. If d is nil, a zero net.Dialer is used.
+func NewDialer(d ContextDialer, b *Breaker) *Dialer {
+	if d == nil {
+		d = &net.Dialer{}
+	}
+	return &Dialer{d: d, b: b}
+}
+
+// Dial connects to the address on the named network. See net.Dial for a
+// description of the network and address parameters.
+func (d *Dialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+// DialContext connects to the address on the named network using the
+// provided context.
+func (d *Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	return ProtectResult(ctx, d.b, func(ctx context.Context) (net.Conn, error) {
+		return d.d.DialContext(ctx, network, address)
+	}, nil)
+}
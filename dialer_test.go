@@ -0,0 +1,60 @@
+package breaker
+
+import (
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"testing"
+)
+
+func TestDialer(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	addr := l.Addr().String()
+
+	cb := NewBreaker().TripAfter(1)
+	d := NewDialer(nil, cb)
+
+	conn, err := d.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	conn.Close()
+
+	if cb.SuccessCount() != 1 {
+		t.Fatalf("unexpected success count: want %d, got %d", 1, cb.SuccessCount())
+	}
+
+	// refused connections trip the breaker
+	l.Close()
+	if _, err := d.Dial("tcp", addr); err == nil {
+		t.Fatalf("unexpected error: want error, got nil")
+	}
+
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+
+	if _, err := d.Dial("tcp", addr); !errors.Is(err, ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+}
+
+func ExampleNewDialer() {
+	cb := NewBreaker()
+	d := NewDialer(&net.Dialer{}, cb)
+
+	client := &http.Client{
+		Transport: &http.Transport{DialContext: d.DialContext},
+	}
+
+	resp, err := client.Get("https://example.com")
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	defer resp.Body.Close()
+}
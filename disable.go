@@ -0,0 +1,31 @@
+package breaker
+
+// Disable puts the breaker into a passthrough mode in which every call
+// protected by Protect or ProtectContext is made directly, without being
+// rejected or recorded. The breaker's state and counters are left as
+// they were. It is useful for staged rollouts and as an emergency
+// bypass, without changing every call site.
+func (b *Breaker) Disable() {
+	b.lock()
+	defer b.mu.Unlock()
+	b.disabled = true
+}
+
+// Enable ends the passthrough mode started by Disable.
+func (b *Breaker) Enable() {
+	b.lock()
+	defer b.mu.Unlock()
+	b.disabled = false
+}
+
+// Disabled reports whether the breaker is in passthrough mode.
+func (b *Breaker) Disabled() bool {
+	return b.isDisabled()
+}
+
+// isDisabled reports whether the breaker is in passthrough mode.
+func (b *Breaker) isDisabled() bool {
+	b.lock()
+	defer b.mu.Unlock()
+	return b.disabled
+}
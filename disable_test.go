@@ -0,0 +1,96 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDisable(t *testing.T) {
+	cb := NewBreaker().TripAfter(1)
+	cb.Protect(errorFunc)
+	cb.Disable()
+
+	if cb.Disabled() == false {
+		t.Fatalf("unexpected disabled state: want disabled")
+	}
+
+	// calls pass through an open breaker
+	if err := cb.Protect(successFunc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// failures are returned but not recorded
+	cb.Enable()
+	cb.ForceClose()
+	cb.Release()
+	cb.Disable()
+	for i := 0; i < 5; i++ {
+		if err := cb.Protect(errorFunc); err == nil {
+			t.Fatalf("unexpected error: want error, got nil")
+		}
+	}
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+
+	done, err := cb.Allow()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	done(false)
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+
+	cb.Enable()
+	if err := cb.Protect(errorFunc); err == nil {
+		t.Fatalf("unexpected error: want error, got nil")
+	}
+	if err := cb.Protect(successFunc); !errors.Is(err, ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+}
+
+func TestDisableEveryPath(t *testing.T) {
+	cb := NewBreaker().TripAfter(1)
+	cb.Protect(errorFunc)
+	cb.Disable()
+
+	r, err := cb.Reserve(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.Protect(successFunc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	scope := NewRequestScope()
+	if err := scope.Protect(cb, successFunc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, decided := scope.Allowed(cb); decided {
+		t.Fatalf("unexpected scope decision: want undecided")
+	}
+
+	if n := cb.ImportOutcomes([]Outcome{{}, {}}); n != 0 {
+		t.Fatalf("unexpected imported outcomes: want %d, got %d", 0, n)
+	}
+
+	cb.Enable()
+	cb.Reset()
+	cb.Disable()
+	if cb.RecordIfClosed(Outcome{Err: errors.New("failed")}) {
+		t.Fatalf("unexpected record: want outcome ignored")
+	}
+	err = cb.ProtectContext(context.Background(), func(ctx context.Context) error {
+		return scope.ProtectContext(ctx, cb, func(context.Context) error { return nil })
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cb.SuccessCount() != 0 || cb.FailCount() != 0 {
+		t.Fatalf("unexpected counts: want 0/0, got %d/%d", cb.SuccessCount(), cb.FailCount())
+	}
+}
@@ -0,0 +1,15 @@
+package breaker
+
+// Do calls the function f protected by the breaker b and returns its
+// result. It behaves like Protect but allows protected calls to return a
+// typed value without capturing it in a closure. If the breaker is open,
+// the zero value of T is returned along with the error.
+func Do[T any](b *Breaker, f func() (T, error)) (T, error) {
+	var v T
+	err := b.Protect(func() error {
+		var err error
+		v, err = f()
+		return err
+	})
+	return v, err
+}
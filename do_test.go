@@ -0,0 +1,59 @@
+package breaker
+
+import (
+	"errors"
+	"log"
+	"testing"
+)
+
+func TestDo(t *testing.T) {
+	cb := NewBreaker()
+
+	v, err := Do(cb, func() (int, error) {
+		return 42, nil
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected response: %v", err)
+	}
+
+	if v != 42 {
+		t.Fatalf("unexpected value: want %d, got %d", 42, v)
+	}
+
+	if cb.SuccessCount() != 1 {
+		t.Fatalf("unexpected success count: want %d, got %d", 1, cb.SuccessCount())
+	}
+}
+
+func TestDoOpen(t *testing.T) {
+	cb := NewBreaker()
+	cb.Open()
+
+	v, err := Do(cb, func() (string, error) {
+		return "value", nil
+	})
+
+	if !errors.Is(err, ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+
+	if v != "" {
+		t.Fatalf("unexpected value: want %q, got %q", "", v)
+	}
+}
+
+func ExampleDo() {
+	cb := NewBreaker()
+
+	v, err := Do(cb, func() (string, error) {
+		// make the function call you are trying to protect
+		// and return its result
+		return "result", nil
+	})
+
+	if err != nil {
+		log.Println(err)
+	}
+	log.Println(v)
+}
@@ -0,0 +1,38 @@
+package breaker
+
+import (
+	"context"
+	"os/exec"
+)
+
+// RunCommand runs cmd protected by the breaker b and waits for it to
+// complete. It is intended for services that shell out to flaky tools or
+// sidecar binaries. A command that fails to start, exits with a non-zero
+// status or is terminated by a signal is counted as a failure, and the
+// error is returned as it would be by cmd.Run.
+//
+// If ctx is done before the command completes, the process is killed and
+// the context's error is returned. An expired deadline is counted as a
+// failure, while a cancelled context is not. If the call is rejected,
+// the command is not started.
+func RunCommand(ctx context.Context, b *Breaker, cmd *exec.Cmd) error {
+	return b.ProtectContext(ctx, func(ctx context.Context) error {
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+
+		waited := make(chan error, 1)
+		go func() {
+			waited <- cmd.Wait()
+		}()
+
+		select {
+		case err := <-waited:
+			return err
+		case <-ctx.Done():
+			cmd.Process.Kill()
+			<-waited
+			return ctx.Err()
+		}
+	})
+}
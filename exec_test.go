@@ -0,0 +1,46 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestRunCommand(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	cb := NewBreaker().TripAfter(2)
+
+	if err := RunCommand(context.Background(), cb, exec.Command("sh", "-c", "exit 0")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var exitErr *exec.ExitError
+	err := RunCommand(context.Background(), cb, exec.Command("sh", "-c", "exit 3"))
+	if errors.As(err, &exitErr) == false || exitErr.ExitCode() != 3 {
+		t.Fatalf("unexpected error: want exit status 3, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = RunCommand(ctx, cb, exec.Command("sh", "-c", "sleep 5"))
+	if errors.Is(err, context.DeadlineExceeded) == false {
+		t.Fatalf("unexpected error: want %v, got %v", context.DeadlineExceeded, err)
+	}
+
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+
+	cmd := exec.Command("sh", "-c", "exit 0")
+	if err := RunCommand(context.Background(), cb, cmd); !errors.Is(err, ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+	if cmd.Process != nil {
+		t.Fatalf("unexpected process: want command not started")
+	}
+}
@@ -0,0 +1,85 @@
+package breaker
+
+import (
+	"context"
+	"sync"
+)
+
+// FanOut is a collection of protected calls made concurrently against
+// the same dependency. It behaves like errgroup.Group: every call is
+// counted by the shared breaker, and the first call to return an error
+// cancels the context returned alongside the FanOut. Calls that fail
+// after the context has been cancelled are not counted, so a single
+// failure is not multiplied by the siblings it cancels.
+//
+// The context is also cancelled if the breaker trips while calls are
+// in flight, even when the trip was caused by a caller outside the
+// group, so that remaining calls can give up promptly.
+type FanOut struct {
+	b      *Breaker
+	ctx    context.Context
+	cancel func()
+	wg     sync.WaitGroup
+	once   sync.Once
+	err    error
+}
+
+// FanOut returns a new FanOut protected by the breaker and an associated
+// context derived from ctx. The derived context is cancelled the first
+// time a call in the group returns an error, the first time the breaker
+// trips, or when Wait returns, whichever occurs first.
+func (b *Breaker) FanOut(ctx context.Context) (*FanOut, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	g := &FanOut{b: b, ctx: ctx, cancel: cancel}
+
+	tripped := b.tripped()
+	go func() {
+		select {
+		case <-tripped:
+			b.lock()
+			err := b.openError()
+			b.mu.Unlock()
+			g.setErr(err)
+		case <-ctx.Done():
+		}
+	}()
+
+	return g, ctx
+}
+
+// Go calls the given function in a new goroutine, protected by the
+// breaker. The function is passed the context returned by FanOut and
+// should return early when it is cancelled.
+func (g *FanOut) Go(f func(ctx context.Context) error) {
+	g.wg.Add(1)
+
+	go func() {
+		defer g.wg.Done()
+
+		if err := g.b.ProtectContext(g.ctx, f); err != nil {
+			g.setErr(err)
+		}
+	}()
+}
+
+// Wait blocks until all function calls from the Go method have
+// returned, then returns the first error encountered, if any. If the
+// breaker tripped while the group was running, the error indicates
+// that the breaker is open.
+func (g *FanOut) Wait() error {
+	g.wg.Wait()
+
+	// a nil error leaves any earlier error in place; the call ensures the
+	// context is cancelled and no later trip can modify the result
+	g.setErr(nil)
+	return g.err
+}
+
+// setErr records the first error seen by the group and cancels the
+// group's context.
+func (g *FanOut) setErr(err error) {
+	g.once.Do(func() {
+		g.err = err
+		g.cancel()
+	})
+}
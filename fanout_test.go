@@ -0,0 +1,118 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"testing"
+)
+
+func TestFanOutSuccess(t *testing.T) {
+	cb := NewBreaker()
+	g, ctx := cb.FanOut(context.Background())
+
+	for i := 0; i < 3; i++ {
+		g.Go(func(context.Context) error { return nil })
+	}
+
+	if err := g.Wait(); err != nil {
+		t.Fatalf("unexpected response: %v", err)
+	}
+
+	if cb.SuccessCount() != 3 {
+		t.Fatalf("unexpected success count: want %d, got %d", 3, cb.SuccessCount())
+	}
+
+	if ctx.Err() == nil {
+		t.Fatalf("unexpected context state: context not cancelled after Wait")
+	}
+}
+
+func TestFanOutError(t *testing.T) {
+	cb := NewBreaker()
+	g, _ := cb.FanOut(context.Background())
+
+	g.Go(func(context.Context) error { return errorFunc() })
+	g.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	err := g.Wait()
+	if err == nil {
+		t.Fatalf("unexpected response: no error returned")
+	}
+
+	if err.Error() != errorFunc().Error() {
+		t.Fatalf("unexpected error: want %v, got %v", errorFunc(), err)
+	}
+}
+
+func TestFanOutCancelledSiblings(t *testing.T) {
+	cb := NewBreaker().TripAfter(3)
+	g, _ := cb.FanOut(context.Background())
+
+	for i := 0; i < 4; i++ {
+		g.Go(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}
+	g.Go(func(context.Context) error { return errorFunc() })
+
+	if err := g.Wait(); err == nil {
+		t.Fatalf("unexpected response: no error returned")
+	}
+
+	// only the failing call is counted, not the siblings it cancelled
+	if cb.FailCount() != 1 {
+		t.Fatalf("unexpected fail count: want %d, got %d", 1, cb.FailCount())
+	}
+
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected final state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+}
+
+func TestFanOutTrip(t *testing.T) {
+	cb := NewBreaker().TripAfter(1)
+	g, _ := cb.FanOut(context.Background())
+
+	started := make(chan struct{})
+	g.Go(func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return nil
+	})
+
+	// trip the breaker from outside the group
+	<-started
+	cb.Protect(errorFunc)
+
+	err := g.Wait()
+	var oe *OpenError
+	if errors.As(err, &oe) == false || oe.State != StateOpen {
+		t.Fatalf("unexpected error: want *OpenError for %v, got %v", StateOpen, err)
+	}
+
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected final state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+}
+
+func ExampleBreaker_FanOut() {
+	cb := NewBreaker()
+	g, _ := cb.FanOut(context.Background())
+
+	for i := 0; i < 3; i++ {
+		g.Go(func(ctx context.Context) error {
+			// make the function call you are trying to protect, giving
+			// up early if ctx is cancelled, and return an error on failure
+			return ctx.Err()
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		log.Println(err)
+	}
+}
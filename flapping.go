@@ -0,0 +1,70 @@
+package breaker
+
+import "time"
+
+// DetectFlapping configures the breaker to report that it is flapping
+// when it trips n or more times within a period t. A breaker that
+// repeatedly moves between the open and closed states is usually
+// misconfigured and can amplify instability in the protected system.
+func (b *Breaker) DetectFlapping(n int, t time.Duration) *Breaker {
+	return b.configure(func() {
+		b.flapLimit = n
+		b.flapWindow = t
+	})
+}
+
+// DampenFlapping configures the breaker to remain open for an additional
+// period t before attempting to reset while it is flapping. It has no
+// effect unless flapping detection has been configured with
+// DetectFlapping.
+func (b *Breaker) DampenFlapping(t time.Duration) *Breaker {
+	return b.configure(func() {
+		b.flapDampen = t
+	})
+}
+
+// Flapping reports whether the breaker has tripped more often than
+// allowed by DetectFlapping within the configured period.
+func (b *Breaker) Flapping() bool {
+	b.lock()
+	defer b.mu.Unlock()
+	return b.flapping()
+}
+
+// flapping reports whether the breaker is flapping
+func (b *Breaker) flapping() bool {
+	if b.flapLimit <= 0 {
+		return false
+	}
+	b.pruneTrips(time.Now())
+	return len(b.trips) >= b.flapLimit
+}
+
+// recordTrip records the time the breaker tripped if flapping detection
+// is enabled
+func (b *Breaker) recordTrip(t time.Time) {
+	if b.flapLimit <= 0 {
+		return
+	}
+	b.trips = append(b.trips, t)
+	b.pruneTrips(t)
+}
+
+// pruneTrips discards trips that fall outside the flapping window
+func (b *Breaker) pruneTrips(now time.Time) {
+	cutoff := now.Add(-b.flapWindow)
+	i := 0
+	for i < len(b.trips) && b.trips[i].Before(cutoff) {
+		i++
+	}
+	b.trips = b.trips[i:]
+}
+
+// dampened reports whether a flapping breaker should remain open beyond
+// the time allowed by its reset policy
+func (b *Breaker) dampened() bool {
+	if b.flapDampen <= 0 || b.flapping() == false {
+		return false
+	}
+	return time.Since(b.openedAt) < b.flapDampen
+}
@@ -0,0 +1,67 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFlapping(t *testing.T) {
+	cb := NewBreaker().TripAfter(1).ResetAfter(time.Millisecond).DetectFlapping(2, time.Second)
+
+	cb.Protect(errorFunc)
+	if cb.Flapping() {
+		t.Fatalf("unexpected flapping indicator: want %t, got %t", false, cb.Flapping())
+	}
+
+	// fail the probe so that the breaker trips a second time
+	time.Sleep(5 * time.Millisecond)
+	cb.Protect(errorFunc)
+
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+
+	if cb.Flapping() == false {
+		t.Fatalf("unexpected flapping indicator: want %t, got %t", true, cb.Flapping())
+	}
+}
+
+func TestFlappingWindow(t *testing.T) {
+	cb := NewBreaker().TripAfter(1).ResetAfter(time.Millisecond).DetectFlapping(2, 10*time.Millisecond)
+
+	cb.Protect(errorFunc)
+
+	// wait for the first trip to fall outside the window
+	time.Sleep(20 * time.Millisecond)
+	cb.Protect(errorFunc)
+
+	if cb.Flapping() {
+		t.Fatalf("unexpected flapping indicator: want %t, got %t", false, cb.Flapping())
+	}
+}
+
+func TestDampenFlapping(t *testing.T) {
+	cb := NewBreaker().TripAfter(1).ResetAfter(time.Millisecond).
+		DetectFlapping(2, time.Second).DampenFlapping(50 * time.Millisecond)
+
+	cb.Protect(errorFunc)
+	time.Sleep(5 * time.Millisecond)
+	cb.Protect(errorFunc)
+
+	// the reset policy allows a probe but the breaker is flapping
+	time.Sleep(5 * time.Millisecond)
+	err := cb.Protect(successFunc)
+	if err == nil {
+		t.Fatalf("unexpected response: no error returned")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	err = cb.Protect(successFunc)
+	if err != nil {
+		t.Fatalf("unexpected response: %v", err)
+	}
+
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected final state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+}
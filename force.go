@@ -0,0 +1,47 @@
+package breaker
+
+// ForceOpen opens the breaker and pins it open until Release is called.
+// Every call is rejected while the breaker is pinned open, however long
+// ago it tripped. Operators can use it to take a dependency out of
+// rotation during an incident.
+func (b *Breaker) ForceOpen() {
+	b.force(StateOpen)
+}
+
+// ForceClose closes the breaker and pins it closed until Release is
+// called. Calls continue to be counted but cannot trip the breaker.
+// Operators can use it to override a misbehaving breaker.
+func (b *Breaker) ForceClose() {
+	b.force(StateClosed)
+}
+
+// force moves the breaker to the given state and stops it from making
+// automatic transitions.
+func (b *Breaker) force(to State) {
+	b.lock()
+	defer b.unlock()
+
+	b.forced = true
+	if b.state != to {
+		b.transition(to)
+	}
+}
+
+// Release lets a breaker pinned by ForceOpen or ForceClose make
+// automatic transitions again. The breaker remains in its current
+// state until its policies move it on. Reset, Close and Open also
+// release the breaker, since they move it to a state of the caller's
+// choosing.
+func (b *Breaker) Release() {
+	b.lock()
+	defer b.mu.Unlock()
+	b.forced = false
+}
+
+// Forced reports whether the breaker is pinned in its current state by
+// ForceOpen or ForceClose.
+func (b *Breaker) Forced() bool {
+	b.lock()
+	defer b.mu.Unlock()
+	return b.forced
+}
@@ -0,0 +1,95 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestForceOpen(t *testing.T) {
+	cb := NewBreaker().ResetAfter(10 * time.Millisecond)
+	cb.ForceOpen()
+
+	if cb.Forced() == false {
+		t.Fatalf("unexpected forced state: want forced")
+	}
+
+	// calls are rejected after the reset timeout has expired
+	time.Sleep(20 * time.Millisecond)
+	if err := cb.Protect(successFunc); !errors.Is(err, ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+
+	// even probes are rejected
+	if err := cb.Protect(successFunc, AsProbe()); !errors.Is(err, ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+
+	cb.Release()
+	if err := cb.Protect(successFunc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+}
+
+func TestForceClose(t *testing.T) {
+	cb := NewBreaker().TripAfter(1)
+	cb.ForceClose()
+
+	for i := 0; i < 3; i++ {
+		if err := cb.Protect(errorFunc); errors.Is(err, ErrOpen) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+
+	if cb.FailCount() != 3 {
+		t.Fatalf("unexpected fail count: want %d, got %d", 3, cb.FailCount())
+	}
+
+	// the next failure trips the breaker once it is released
+	cb.Release()
+	cb.Protect(errorFunc)
+
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+}
+
+func TestForceOpenAvailable(t *testing.T) {
+	cb := NewBreaker().ResetAfter(time.Millisecond)
+	cb.ForceOpen()
+	time.Sleep(5 * time.Millisecond)
+
+	if p := cb.AdmissionProbability(); p != 0 {
+		t.Fatalf("unexpected admission probability: want %v, got %v", 0.0, p)
+	}
+}
+
+func TestForceManualTransition(t *testing.T) {
+	cb := NewBreaker().TripAfter(1)
+	cb.ForceOpen()
+	cb.Reset()
+
+	if cb.Forced() {
+		t.Fatalf("unexpected forced state: want released")
+	}
+
+	// the breaker trips as usual after a manual transition
+	cb.Protect(errorFunc)
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+
+	cb.ForceClose()
+	cb.Open()
+	if cb.Forced() {
+		t.Fatalf("unexpected forced state: want released")
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/billglover/breaker
+
+go 1.23
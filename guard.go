@@ -0,0 +1,77 @@
+package breaker
+
+// Reason describes why the breaker is making an automatic transition.
+type Reason int
+
+const (
+	// ReasonTripped indicates that the trip policy was met in the closed
+	// state.
+	ReasonTripped Reason = iota
+
+	// ReasonResetTimeout indicates that the reset timeout has expired and
+	// the breaker is ready to let a probe through.
+	ReasonResetTimeout
+
+	// ReasonProbeFailed indicates that a call failed in the partially
+	// open state.
+	ReasonProbeFailed
+
+	// ReasonProbeSucceeded indicates that enough calls succeeded in the
+	// partially open state to close the breaker.
+	ReasonProbeSucceeded
+)
+
+func (r Reason) String() string {
+	switch r {
+	case ReasonTripped:
+		return "tripped"
+	case ReasonResetTimeout:
+		return "reset timeout"
+	case ReasonProbeFailed:
+		return "probe failed"
+	case ReasonProbeSucceeded:
+		return "probe succeeded"
+	default:
+		return "unknown"
+	}
+}
+
+// TransitionGuard registers a function that is consulted before every
+// automatic transition and may veto it by returning false, for example
+// to keep the breaker from closing during a declared incident. A vetoed
+// transition leaves the breaker in its current state; a veto of the
+// move to the partially open state rejects the call that triggered it.
+//
+// Manual transitions made with Open, Close and Reset bypass the guard.
+// The guard is called with the breaker's lock held and must not call
+// methods on the breaker. Passing nil removes the guard.
+func (b *Breaker) TransitionGuard(guard func(from, to State, reason Reason) bool) *Breaker {
+	return b.configure(func() {
+		b.guard = guard
+	})
+}
+
+// permit reports whether an automatic transition from the current state
+// is allowed, either because the breaker has been forced into its state
+// or because the guard vetoes it. It must be called with the lock held.
+func (b *Breaker) permit(to State) bool {
+	if b.forced {
+		return false
+	}
+	if b.guard == nil {
+		return true
+	}
+
+	var reason Reason
+	switch {
+	case to == StatePartial:
+		reason = ReasonResetTimeout
+	case to == StateClosed:
+		reason = ReasonProbeSucceeded
+	case b.state == StatePartial:
+		reason = ReasonProbeFailed
+	default:
+		reason = ReasonTripped
+	}
+	return b.guard(b.state, to, reason)
+}
@@ -0,0 +1,150 @@
+package breaker
+
+import (
+	"errors"
+	"log"
+	"testing"
+	"time"
+)
+
+func TestTransitionGuardVetoTrip(t *testing.T) {
+	var reasons []Reason
+	cb := NewBreaker().TripAfter(1).TransitionGuard(func(from, to State, reason Reason) bool {
+		reasons = append(reasons, reason)
+		return false
+	})
+
+	cb.Protect(errorFunc)
+
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+
+	if len(reasons) != 1 || reasons[0] != ReasonTripped {
+		t.Fatalf("unexpected reasons: want %v, got %v", []Reason{ReasonTripped}, reasons)
+	}
+
+	// manual transitions bypass the guard
+	cb.Open()
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+}
+
+func TestTransitionGuardVetoClose(t *testing.T) {
+	incident := true
+	cb := NewBreaker().TripAfter(1).ResetAfter(10 * time.Millisecond)
+	cb.TransitionGuard(func(from, to State, reason Reason) bool {
+		return to != StateClosed || incident == false
+	})
+
+	cb.Protect(errorFunc)
+	time.Sleep(20 * time.Millisecond)
+
+	// the probe succeeds but the breaker may not close
+	if err := cb.Protect(successFunc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cb.CurrentState() != StatePartial {
+		t.Fatalf("unexpected state: want %v, got %v", StatePartial, cb.CurrentState())
+	}
+
+	cb.TransitionGuard(nil)
+	cb.Protect(successFunc)
+
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+}
+
+func TestTransitionGuardVetoReset(t *testing.T) {
+	cb := NewBreaker().TripAfter(1).ResetAfter(10 * time.Millisecond)
+	cb.TransitionGuard(func(from, to State, reason Reason) bool {
+		return reason != ReasonResetTimeout
+	})
+
+	cb.Protect(errorFunc)
+	time.Sleep(20 * time.Millisecond)
+
+	if err := cb.Protect(successFunc); !errors.Is(err, ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+}
+
+func TestTransitionGuardLateFailure(t *testing.T) {
+	var calls []string
+	cb := NewBreaker().TripAfter(1).TransitionGuard(func(from, to State, reason Reason) bool {
+		calls = append(calls, from.String()+">"+to.String())
+		return true
+	})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- cb.Protect(func() error {
+			close(started)
+			<-release
+			return errors.New("late failure")
+		})
+	}()
+
+	// trip the breaker while the first call is still in flight
+	<-started
+	cb.Protect(errorFunc)
+	close(release)
+	<-done
+
+	// the late failure does not ask the guard to open an open breaker
+	if len(calls) != 1 || calls[0] != "closed>open" {
+		t.Fatalf("unexpected guard calls: want %v, got %v", []string{"closed>open"}, calls)
+	}
+
+	if cb.IllegalTransitions() != 0 {
+		t.Fatalf("unexpected illegal transition count: want %d, got %d", 0, cb.IllegalTransitions())
+	}
+}
+
+func TestReasons(t *testing.T) {
+	if ReasonTripped.String() != "tripped" {
+		t.Fatalf("unexpected reason description: want %s, got %s", "tripped", ReasonTripped.String())
+	}
+
+	if ReasonResetTimeout.String() != "reset timeout" {
+		t.Fatalf("unexpected reason description: want %s, got %s", "reset timeout", ReasonResetTimeout.String())
+	}
+
+	if ReasonProbeFailed.String() != "probe failed" {
+		t.Fatalf("unexpected reason description: want %s, got %s", "probe failed", ReasonProbeFailed.String())
+	}
+
+	if ReasonProbeSucceeded.String() != "probe succeeded" {
+		t.Fatalf("unexpected reason description: want %s, got %s", "probe succeeded", ReasonProbeSucceeded.String())
+	}
+
+	if Reason(30).String() != "unknown" {
+		t.Fatalf("unexpected reason description: want %s, got %s", "unknown", Reason(30).String())
+	}
+}
+
+func ExampleBreaker_TransitionGuard() {
+	var incident bool
+	cb := NewBreaker().TransitionGuard(func(from, to State, reason Reason) bool {
+		// refuse to close the breaker automatically during an incident
+		if to == StateClosed && incident {
+			log.Printf("vetoed transition from %v to %v: %v", from, to, reason)
+			return false
+		}
+		return true
+	})
+
+	cb.Protect(func() error {
+		// make the function call you are trying to protect
+		return nil
+	})
+}
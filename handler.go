@@ -0,0 +1,58 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// NewHandler returns an http.Handler that serves requests using h,
+// protected by the breaker b. Responses with a 5xx status code are
+// counted as failures. While the breaker is open, h is not called and
+// the handler responds with 503 Service Unavailable and a Retry-After
+// header giving the number of seconds until the breaker is next ready
+// to let a request through.
+func NewHandler(h http.Handler, b *Breaker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := ProtectResult(r.Context(), b, func(ctx context.Context) (int, error) {
+			rw := &statusWriter{ResponseWriter: w}
+			h.ServeHTTP(rw, r.WithContext(ctx))
+			return rw.status, nil
+		}, func(status int, _ error) bool {
+			return status >= 500
+		})
+
+		if errors.Is(err, ErrOpen) {
+			if ra := RetryAfterHeader(b); ra != "" {
+				w.Header().Set("Retry-After", ra)
+			}
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		}
+	})
+}
+
+// statusWriter records the status code written to a ResponseWriter.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap allows http.ResponseController to reach the underlying
+// ResponseWriter.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,123 @@
+package breaker
+
+import (
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandler(t *testing.T) {
+	status := http.StatusOK
+	calls := 0
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(status)
+	})
+
+	cb := NewBreaker().TripAfter(2).ResetAfter(1500 * time.Millisecond)
+	handler := NewHandler(h, cb)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status: want %d, got %d", http.StatusOK, w.Code)
+	}
+
+	if cb.SuccessCount() != 1 {
+		t.Fatalf("unexpected success count: want %d, got %d", 1, cb.SuccessCount())
+	}
+
+	// server errors are passed through and counted as failures
+	status = http.StatusInternalServerError
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("unexpected status: want %d, got %d", http.StatusInternalServerError, w.Code)
+		}
+	}
+
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+
+	// the handler is not called while the breaker is open
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected status: want %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+
+	if ra := w.Header().Get("Retry-After"); ra != "2" {
+		t.Fatalf("unexpected Retry-After: want %q, got %q", "2", ra)
+	}
+
+	if calls != 3 {
+		t.Fatalf("unexpected call count: want %d, got %d", 3, calls)
+	}
+}
+
+func TestHandlerNested(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	cb := NewBreaker().TripAfter(1).ResetAfter(10 * time.Millisecond).MaxProbes(1)
+	handler := NewHandler(NewHandler(h, cb), cb)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	// the inner handler does not count the request a second time
+	if cb.SuccessCount() != 1 {
+		t.Fatalf("unexpected success count: want %d, got %d", 1, cb.SuccessCount())
+	}
+
+	// nor does it take a second probe slot
+	cb.Open()
+	time.Sleep(15 * time.Millisecond)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status: want %d, got %d", http.StatusOK, w.Code)
+	}
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+}
+
+func TestHandlerImplicitStatus(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+
+	cb := NewBreaker()
+	w := httptest.NewRecorder()
+	NewHandler(h, cb).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if cb.SuccessCount() != 1 {
+		t.Fatalf("unexpected success count: want %d, got %d", 1, cb.SuccessCount())
+	}
+
+	if w.Body.String() != "ok" {
+		t.Fatalf("unexpected body: want %q, got %q", "ok", w.Body.String())
+	}
+}
+
+func ExampleNewHandler() {
+	cb := NewBreaker()
+
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// do the work you are trying to protect
+		w.Write([]byte("hello"))
+	})
+
+	http.Handle("/", NewHandler(h, cb))
+	log.Fatal(http.ListenAndServe(":8080", nil))
+}
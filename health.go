@@ -0,0 +1,103 @@
+package breaker
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+// HealthCheck is an http.Handler reporting the state of the breakers
+// protecting an instance's dependencies, in a form suitable for the
+// health checks of an external load balancer. The response status code
+// is chosen by the worst state among the critical dependencies so that
+// a load balancer can stop routing to an instance whose critical
+// breaker is open. The body lists the state of every dependency.
+//
+//	h := NewHealthCheck().Add("payments", payments, true)
+//	http.Handle("/lb-health", h)
+type HealthCheck struct {
+	mu       sync.Mutex
+	names    []string
+	breakers map[string]*Breaker
+	critical map[string]bool
+	codes    map[State]int
+}
+
+// NewHealthCheck returns a new health check with no dependencies. By
+// default it responds 200 OK while critical breakers are closed or
+// partially open and 503 Service Unavailable if any of them are open.
+func NewHealthCheck() *HealthCheck {
+	return &HealthCheck{
+		breakers: map[string]*Breaker{},
+		critical: map[string]bool{},
+		codes: map[State]int{
+			StateClosed:  http.StatusOK,
+			StatePartial: http.StatusOK,
+			StateOpen:    http.StatusServiceUnavailable,
+		},
+	}
+}
+
+// Add reports the breaker b under the dependency name. If critical is
+// true, the state of the breaker determines the response status code.
+// Adding a name a second time replaces the earlier breaker.
+func (h *HealthCheck) Add(name string, b *Breaker, critical bool) *HealthCheck {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.breakers[name]; !ok {
+		h.names = append(h.names, name)
+	}
+	h.breakers[name] = b
+	h.critical[name] = critical
+	return h
+}
+
+// StatusCode configures the status code returned when the worst state
+// among the critical dependencies is s.
+func (h *HealthCheck) StatusCode(s State, code int) *HealthCheck {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.codes[s] = code
+	return h
+}
+
+// healthReport is the body of a health check response.
+type healthReport struct {
+	Status       string            `json:"status"`
+	Dependencies map[string]string `json:"dependencies"`
+}
+
+// ServeHTTP implements http.Handler.
+func (h *HealthCheck) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	worst := StateClosed
+	report := healthReport{Dependencies: make(map[string]string, len(h.names))}
+	for _, name := range h.names {
+		s := h.breakers[name].CurrentState()
+		report.Dependencies[name] = s.String()
+		if h.critical[name] && severity(s) > severity(worst) {
+			worst = s
+		}
+	}
+	code := h.codes[worst]
+	h.mu.Unlock()
+
+	report.Status = worst.String()
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(report)
+}
+
+// severity orders states from healthy to unhealthy.
+func severity(s State) int {
+	switch s {
+	case StateClosed:
+		return 0
+	case StatePartial:
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,77 @@
+package breaker
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealthCheck(t *testing.T) {
+	payments := NewBreaker()
+	search := NewBreaker()
+	h := NewHealthCheck().Add("payments", payments, true).Add("search", search, false)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/lb-health", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status: want %d, got %d", http.StatusOK, w.Code)
+	}
+
+	// a non-critical dependency doesn't affect the status code
+	search.Open()
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/lb-health", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status: want %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var report healthReport
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.Status != "closed" {
+		t.Fatalf("unexpected status: want %q, got %q", "closed", report.Status)
+	}
+
+	if report.Dependencies["search"] != "open" {
+		t.Fatalf("unexpected dependency state: want %q, got %q", "open", report.Dependencies["search"])
+	}
+
+	payments.Open()
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/lb-health", nil))
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected status: want %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+}
+
+func TestHealthCheckStatusCode(t *testing.T) {
+	cb := NewBreaker()
+	h := NewHealthCheck().Add("db", cb, true).StatusCode(StateOpen, http.StatusTooManyRequests)
+
+	cb.Open()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/lb-health", nil))
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("unexpected status: want %d, got %d", http.StatusTooManyRequests, w.Code)
+	}
+}
+
+func ExampleHealthCheck() {
+	payments := NewBreaker()
+	search := NewBreaker()
+
+	h := NewHealthCheck().
+		Add("payments", payments, true).
+		Add("search", search, false)
+
+	http.Handle("/lb-health", h)
+	log.Fatal(http.ListenAndServe(":8080", nil))
+}
@@ -0,0 +1,68 @@
+package breaker
+
+import "time"
+
+// Outcome is the result of a call to the protected system made outside
+// of Protect, for example one reported by a metrics pipeline or read
+// from an access log.
+type Outcome struct {
+	// Err is the error returned by the call, or nil if it succeeded. An
+	// outcome with the error returned by Degraded is counted as degraded.
+	Err error
+
+	// Latency is the time taken by the call. A zero value means the
+	// latency is unknown and it is not recorded.
+	Latency time.Duration
+}
+
+// ImportOutcomes records the outcomes of calls made outside of Protect,
+// in order, as if they had been protected by the breaker. This allows
+// breaker decisions to be driven by telemetry collected elsewhere.
+//
+// Outcomes are treated exactly as protected calls would have been. If
+// the breaker is open and not yet ready to reset, an outcome is
+// discarded since the corresponding call would have been rejected. If it
+// is ready to reset, the outcome is treated as a probe. A disabled
+// breaker records nothing. ImportOutcomes returns the number of outcomes
+// recorded.
+func (b *Breaker) ImportOutcomes(outcomes []Outcome) int {
+	b.lock()
+	defer b.unlock()
+
+	if b.disabled {
+		return 0
+	}
+
+	n := 0
+	for _, o := range outcomes {
+		if b.state == StateOpen {
+			if b.ready() == false || b.permit(StatePartial) == false {
+				continue
+			}
+			b.resetCounters()
+			b.transition(StatePartial)
+		}
+
+		b.outcome(o.Err, o.Latency, admission{probe: b.state == StatePartial, epoch: b.epoch})
+		n++
+	}
+	return n
+}
+
+// RecordIfClosed records the outcome of a call made outside of Protect,
+// but only while the breaker is closed and enabled, and reports whether
+// it was recorded. Unlike ImportOutcomes, an outcome is never treated as
+// a probe. It suits calls that were admitted long before they complete,
+// such as messages on a long-lived stream, which say nothing about
+// whether a dependency has recovered from an outage that began after
+// they started.
+func (b *Breaker) RecordIfClosed(o Outcome) bool {
+	b.lock()
+	defer b.unlock()
+
+	if b.disabled || b.state != StateClosed {
+		return false
+	}
+	b.outcome(o.Err, o.Latency, admission{})
+	return true
+}
@@ -0,0 +1,69 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestImportOutcomes(t *testing.T) {
+	cb := NewBreaker().TripAfter(2)
+	failure := errors.New("status 503")
+
+	n := cb.ImportOutcomes([]Outcome{
+		{Err: nil, Latency: 10 * time.Millisecond},
+		{Err: Degraded()},
+		{Err: failure},
+		{Err: failure},
+		{Err: nil},
+	})
+
+	// the final outcome is discarded since the breaker is open
+	if n != 4 {
+		t.Fatalf("unexpected number of outcomes recorded: want %d, got %d", 4, n)
+	}
+
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+
+	if cb.SuccessCount() != 1 || cb.DegradedCount() != 1 || cb.FailCount() != 2 {
+		t.Fatalf("unexpected counts: want %d/%d/%d, got %d/%d/%d", 1, 1, 2, cb.SuccessCount(), cb.DegradedCount(), cb.FailCount())
+	}
+
+	if cb.Latency() != 10*time.Millisecond {
+		t.Fatalf("unexpected latency: want %v, got %v", 10*time.Millisecond, cb.Latency())
+	}
+}
+
+func TestImportOutcomesProbe(t *testing.T) {
+	cb := NewBreaker().TripAfter(1).ResetAfter(10 * time.Millisecond)
+	cb.ImportOutcomes([]Outcome{{Err: errorFunc()}})
+
+	time.Sleep(15 * time.Millisecond)
+	cb.ImportOutcomes([]Outcome{{Err: nil}})
+
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected final state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+}
+
+func TestRecordIfClosed(t *testing.T) {
+	cb := NewBreaker().TripAfter(1).ResetAfter(10 * time.Millisecond)
+
+	if cb.RecordIfClosed(Outcome{Err: errorFunc()}) == false {
+		t.Fatalf("unexpected response: want outcome recorded")
+	}
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+
+	// an outcome is not treated as a probe once the reset timeout expires
+	time.Sleep(15 * time.Millisecond)
+	if cb.RecordIfClosed(Outcome{Err: errorFunc()}) {
+		t.Fatalf("unexpected response: want outcome discarded")
+	}
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+}
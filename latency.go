@@ -0,0 +1,139 @@
+package breaker
+
+import (
+	"slices"
+	"time"
+)
+
+// latencyWeight is the weight given to each new observation in the
+// moving average of call latency.
+const latencyWeight = 0.1
+
+// latencySamples is the number of recent call latencies kept to estimate
+// the latency distribution.
+const latencySamples = 1024
+
+// recommendMargin is the margin added to observed latencies when
+// recommending timeouts.
+const recommendMargin = 0.2
+
+// Latency returns an exponentially weighted moving average of the time
+// taken by protected calls, giving recent calls the most weight. Calls
+// rejected by the breaker or abandoned by the caller are not included.
+// It returns zero until the first call has completed.
+//
+// Callers can use the value as a baseline for adaptive timeouts or
+// hedging delays without instrumenting calls themselves.
+func (b *Breaker) Latency() time.Duration {
+	b.lock()
+	defer b.mu.Unlock()
+	return b.latency
+}
+
+// latencySample is the latency of a single call.
+type latencySample struct {
+	d  time.Duration
+	at time.Time
+}
+
+// observeLatency adds the duration of a call to the moving average and
+// the recent samples
+func (b *Breaker) observeLatency(d time.Duration) {
+	sample := latencySample{d: d, at: time.Now()}
+	if len(b.latencies) < latencySamples {
+		b.latencies = append(b.latencies, sample)
+	} else {
+		b.latencies[b.latencyPos] = sample
+	}
+	b.latencyPos = (b.latencyPos + 1) % latencySamples
+
+	if b.latency == 0 {
+		b.latency = d
+		return
+	}
+	b.latency += time.Duration(latencyWeight * float64(d-b.latency))
+}
+
+// SlowCallThreshold configures the breaker to count successful calls
+// that take longer than t as failures, so that a dependency whose
+// latency is degrading can trip the breaker before it starts returning
+// errors. Slow calls still return their result to the caller and are
+// also counted by SlowCount. A zero value, the default, disables slow
+// call detection.
+func (b *Breaker) SlowCallThreshold(t time.Duration) *Breaker {
+	return b.configure(func() {
+		b.slowCall = t
+	})
+}
+
+// SlowCount returns the current count of successful transactions that
+// exceeded the slow call threshold. These are included in FailCount.
+func (b *Breaker) SlowCount() int {
+	b.lock()
+	defer b.mu.Unlock()
+	return b.slowCount
+}
+
+// Recommendation holds timeouts suggested by the latency of recent calls.
+type Recommendation struct {
+	// Timeout is a suggested timeout for each call, based on the 99.9th
+	// percentile latency plus a margin.
+	Timeout time.Duration
+
+	// SlowCallThreshold is a suggested value for SlowCallThreshold,
+	// based on the 99th percentile latency plus a margin.
+	SlowCallThreshold time.Duration
+
+	// Samples is the number of calls the recommendation is based on. A
+	// recommendation based on only a few calls should be treated with
+	// caution.
+	Samples int
+}
+
+// LatencyWindow limits the calls used by Recommend to those completed
+// within the last t. Latency tends to change over longer timescales than
+// the error rate, so this window is configured independently of the
+// windows used to decide whether to trip, such as SlidingWindow and
+// MaxFailureAge. A zero value, the default, uses the most recent calls
+// regardless of age.
+func (b *Breaker) LatencyWindow(t time.Duration) *Breaker {
+	return b.configure(func() {
+		b.latencyWindow = t
+	})
+}
+
+// Recommend suggests a call timeout and slow call threshold from the
+// latency of up to the last 1024 completed calls, closing the loop
+// between measuring a dependency and configuring the breaker for it.
+// The durations are zero until the first call has completed.
+func (b *Breaker) Recommend() Recommendation {
+	b.lock()
+	var cutoff time.Time
+	if b.latencyWindow > 0 {
+		cutoff = time.Now().Add(-b.latencyWindow)
+	}
+
+	var samples []time.Duration
+	for _, s := range b.latencies {
+		if s.at.Before(cutoff) == false {
+			samples = append(samples, s.d)
+		}
+	}
+	b.mu.Unlock()
+
+	if len(samples) == 0 {
+		return Recommendation{}
+	}
+
+	slices.Sort(samples)
+	quantile := func(q float64) time.Duration {
+		d := samples[int(q*float64(len(samples)-1))]
+		return d + time.Duration(recommendMargin*float64(d))
+	}
+
+	return Recommendation{
+		Timeout:           quantile(0.999),
+		SlowCallThreshold: quantile(0.99),
+		Samples:           len(samples),
+	}
+}
@@ -0,0 +1,111 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLatency(t *testing.T) {
+	cb := NewBreaker()
+
+	if cb.Latency() != 0 {
+		t.Fatalf("unexpected initial latency: want %v, got %v", 0, cb.Latency())
+	}
+
+	cb.Protect(func() error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	})
+
+	if cb.Latency() < 20*time.Millisecond {
+		t.Fatalf("unexpected latency: want at least %v, got %v", 20*time.Millisecond, cb.Latency())
+	}
+
+	// a fast call should move the average towards zero by the latency
+	// weight
+	before := cb.Latency()
+	cb.Protect(successFunc)
+
+	if cb.Latency() >= before || cb.Latency() < before*8/10 {
+		t.Fatalf("unexpected latency: want between %v and %v, got %v", before*8/10, before, cb.Latency())
+	}
+}
+
+func TestSlowCallThreshold(t *testing.T) {
+	cb := NewBreaker().TripAfter(2).SlowCallThreshold(10 * time.Millisecond)
+
+	slowFunc := func() error {
+		time.Sleep(15 * time.Millisecond)
+		return nil
+	}
+
+	err := cb.Protect(slowFunc)
+	if err != nil {
+		t.Fatalf("unexpected response: %v", err)
+	}
+
+	cb.Protect(successFunc)
+
+	if cb.SlowCount() != 1 {
+		t.Fatalf("unexpected slow count: want %d, got %d", 1, cb.SlowCount())
+	}
+
+	if cb.FailCount() != 1 || cb.SuccessCount() != 1 {
+		t.Fatalf("unexpected counts: want %d failed and %d successful, got %d and %d", 1, 1, cb.FailCount(), cb.SuccessCount())
+	}
+
+	cb.Protect(slowFunc)
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected final state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+}
+
+func TestRecommend(t *testing.T) {
+	cb := NewBreaker()
+
+	if r := cb.Recommend(); r != (Recommendation{}) {
+		t.Fatalf("unexpected recommendation: want %+v, got %+v", Recommendation{}, r)
+	}
+
+	var outcomes []Outcome
+	for i := 1; i <= 1000; i++ {
+		outcomes = append(outcomes, Outcome{Latency: time.Duration(i) * time.Millisecond})
+	}
+	cb.ImportOutcomes(outcomes)
+
+	r := cb.Recommend()
+	if r.Samples != 1000 {
+		t.Fatalf("unexpected sample count: want %d, got %d", 1000, r.Samples)
+	}
+
+	if want := 999 * time.Millisecond * 12 / 10; r.Timeout != want {
+		t.Fatalf("unexpected timeout: want %v, got %v", want, r.Timeout)
+	}
+
+	if want := 990 * time.Millisecond * 12 / 10; r.SlowCallThreshold != want {
+		t.Fatalf("unexpected slow call threshold: want %v, got %v", want, r.SlowCallThreshold)
+	}
+
+	// only the most recent calls are kept
+	cb.ImportOutcomes(outcomes)
+	if r := cb.Recommend(); r.Samples != latencySamples {
+		t.Fatalf("unexpected sample count: want %d, got %d", latencySamples, r.Samples)
+	}
+}
+
+func TestLatencyWindow(t *testing.T) {
+	cb := NewBreaker().LatencyWindow(20 * time.Millisecond)
+
+	cb.ImportOutcomes([]Outcome{{Latency: time.Second}, {Latency: time.Second}})
+	time.Sleep(30 * time.Millisecond)
+	cb.ImportOutcomes([]Outcome{{Latency: 10 * time.Millisecond}})
+
+	r := cb.Recommend()
+	if r.Samples != 1 {
+		t.Fatalf("unexpected sample count: want %d, got %d", 1, r.Samples)
+	}
+
+	if want := 12 * time.Millisecond; r.Timeout != want {
+		t.Fatalf("unexpected timeout: want %v, got %v", want, r.Timeout)
+	}
+}
@@ -0,0 +1,203 @@
+package breaker
+
+import (
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+// Observer receives notifications about the activity of a breaker. It
+// is an alternative to Subscribe for consumers that prefer method calls
+// to channel plumbing.
+//
+// Observers are called synchronously, on the goroutine that caused the
+// event, after the breaker has released its internal lock. Events
+// arising from a single call are delivered in the order they occurred.
+// A panic in an observer is recovered and does not affect the breaker
+// or other observers. A slow observer will delay the protected call
+// unless a timeout has been configured with ObserverTimeout.
+type Observer interface {
+	// OnStateChange is called when the breaker moves between states.
+	OnStateChange(from, to State)
+
+	// OnCallRejected is called when a call is rejected because the
+	// breaker is open.
+	OnCallRejected()
+
+	// OnCallCompleted is called when a protected function returns. The
+	// error is the value returned by the protected function.
+	OnCallCompleted(err error)
+}
+
+// Result is the result a call was counted as by the breaker.
+type Result int
+
+// Call results
+const (
+	ResultSuccess Result = iota
+	ResultFailure
+	ResultDegraded
+)
+
+func (r Result) String() string {
+	switch r {
+	case ResultSuccess:
+		return "success"
+	case ResultFailure:
+		return "failure"
+	case ResultDegraded:
+		return "degraded"
+	default:
+		return "unknown"
+	}
+}
+
+// ResultObserver is an Observer that is also notified of each call the
+// breaker counts, with the result it was counted as. Unlike
+// OnCallCompleted, which sees the raw error, calls abandoned by the
+// caller or excluded with NoAccount are not reported, slow calls are
+// reported as failures and degraded calls as degraded, so the results
+// agree with FailCount, SuccessCount and DegradedCount. Observers
+// registered with RegisterObserver that implement this interface receive
+// the results, subject to SampleObservations.
+type ResultObserver interface {
+	Observer
+	OnCallCounted(r Result)
+}
+
+// RegisterObserver registers an observer to receive notifications about
+// the activity of the breaker. Observers are notified in the order in
+// which they were registered.
+func (b *Breaker) RegisterObserver(o Observer) {
+	b.lock()
+	defer b.mu.Unlock()
+	b.observers = append(b.observers, &observer{Observer: o})
+}
+
+// ObserverTimeout bounds the time the breaker waits for each observer to
+// handle a notification. Observers that take longer continue to run in
+// the background but the protected call proceeds without them. While an
+// observer is still handling an earlier notification, further
+// notifications to it are dropped so that a hung observer cannot
+// accumulate goroutines. A zero value, the default, disables the
+// timeout.
+func (b *Breaker) ObserverTimeout(t time.Duration) *Breaker {
+	return b.configure(func() {
+		b.obsTimeout = t
+	})
+}
+
+// SampleObservations configures the proportion of calls, between 0 and
+// 1, for which observers receive OnCallRejected and OnCallCompleted
+// notifications. State changes are always delivered and the breaker's
+// counters remain exact. Sampling keeps the overhead of observers
+// predictable on very busy breakers. By default every call is observed.
+func (b *Breaker) SampleObservations(rate float64) *Breaker {
+	return b.configure(func() {
+		b.obsSkip = 1 - rate
+	})
+}
+
+// sampled reports whether the current call should be observed
+func (b *Breaker) sampled() bool {
+	return b.obsSkip <= 0 || rand.Float64() >= b.obsSkip
+}
+
+// ObserverTimeouts returns the number of observer notifications that
+// exceeded the observer timeout or were dropped because the observer
+// was still busy.
+func (b *Breaker) ObserverTimeouts() int {
+	return int(b.obsDropped.Load())
+}
+
+// observer wraps a registered Observer with the state needed to bound
+// its execution time.
+type observer struct {
+	Observer
+	busy atomic.Bool
+}
+
+// counted notifies observers implementing ResultObserver of the result
+// a call was counted as. It must be called with the lock held.
+func (b *Breaker) counted(r Result) {
+	// the event is only queued if it has an observer, so that observers
+	// not interested in results are not held up by it
+	wanted := false
+	for _, o := range b.observers {
+		if _, ok := o.Observer.(ResultObserver); ok {
+			wanted = true
+		}
+	}
+	if wanted == false || b.sampled() == false {
+		return
+	}
+
+	b.emit(func(o Observer) {
+		if ro, ok := o.(ResultObserver); ok {
+			ro.OnCallCounted(r)
+		}
+	})
+}
+
+// emit queues an event for delivery to observers when the lock is
+// released. It must be called with the lock held.
+func (b *Breaker) emit(e func(Observer)) {
+	if len(b.observers) == 0 {
+		return
+	}
+	b.pending = append(b.pending, e)
+}
+
+// unlock releases the lock and delivers any queued events to observers.
+func (b *Breaker) unlock() {
+	events := b.pending
+	observers := b.observers
+	timeout := b.obsTimeout
+	b.pending = nil
+	b.mu.Unlock()
+
+	for _, e := range events {
+		for _, o := range observers {
+			b.deliver(o, e, timeout)
+		}
+	}
+}
+
+// deliver passes an event to an observer, waiting no longer than the
+// timeout if one is set.
+func (b *Breaker) deliver(o *observer, e func(Observer), timeout time.Duration) {
+	if timeout <= 0 {
+		observe(o.Observer, e)
+		return
+	}
+
+	if o.busy.CompareAndSwap(false, true) == false {
+		b.obsDropped.Add(1)
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer o.busy.Store(false)
+		observe(o.Observer, e)
+	}()
+
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+
+	select {
+	case <-done:
+	case <-t.C:
+		b.obsDropped.Add(1)
+	}
+}
+
+// observe delivers a single event to an observer, recovering from any
+// panic raised by the observer.
+func observe(o Observer, e func(Observer)) {
+	defer func() {
+		recover()
+	}()
+	e(o)
+}
@@ -0,0 +1,194 @@
+package breaker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"testing"
+	"time"
+)
+
+// recorder is an Observer that records the events it receives
+type recorder struct {
+	events []string
+}
+
+func (r *recorder) OnStateChange(from, to State) {
+	r.events = append(r.events, fmt.Sprintf("%s->%s", from, to))
+}
+
+func (r *recorder) OnCallRejected() {
+	r.events = append(r.events, "rejected")
+}
+
+func (r *recorder) OnCallCompleted(err error) {
+	r.events = append(r.events, fmt.Sprintf("completed:%t", err == nil))
+}
+
+// panicker is an Observer that panics on every event
+type panicker struct{}
+
+func (panicker) OnStateChange(from, to State) { panic("state change") }
+func (panicker) OnCallRejected()              { panic("rejected") }
+func (panicker) OnCallCompleted(err error)    { panic("completed") }
+
+func TestObserver(t *testing.T) {
+	cb := NewBreaker().TripAfter(1)
+	r := &recorder{}
+	cb.RegisterObserver(r)
+
+	cb.Protect(successFunc)
+	cb.Protect(errorFunc)
+	cb.Protect(successFunc)
+	cb.Reset()
+
+	want := []string{"completed:true", "completed:false", "closed->open", "rejected", "open->closed"}
+	if fmt.Sprint(r.events) != fmt.Sprint(want) {
+		t.Fatalf("unexpected events: want %v, got %v", want, r.events)
+	}
+}
+
+func TestObserverPanic(t *testing.T) {
+	cb := NewBreaker().TripAfter(1)
+	r := &recorder{}
+	cb.RegisterObserver(panicker{})
+	cb.RegisterObserver(r)
+
+	err := cb.Protect(errorFunc)
+	if err == nil {
+		t.Fatalf("unexpected response: no error returned")
+	}
+
+	if len(r.events) != 2 {
+		t.Fatalf("unexpected event count: want %d, got %d", 2, len(r.events))
+	}
+
+	// the breaker must remain usable after an observer panics
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected final state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+}
+
+func TestSampleObservations(t *testing.T) {
+	cb := NewBreaker().TripAfter(3).SampleObservations(0)
+	r := &recorder{}
+	cb.RegisterObserver(r)
+
+	for i := 0; i < 4; i++ {
+		cb.Protect(errorFunc)
+	}
+
+	// only the state change should be observed
+	want := []string{"closed->open"}
+	if fmt.Sprint(r.events) != fmt.Sprint(want) {
+		t.Fatalf("unexpected events: want %v, got %v", want, r.events)
+	}
+
+	// counters remain exact
+	if cb.FailCount() != 3 {
+		t.Fatalf("unexpected fail count: want %d, got %d", 3, cb.FailCount())
+	}
+}
+
+// blocker is an Observer that blocks until released
+type blocker struct {
+	release chan struct{}
+}
+
+func (b blocker) OnStateChange(from, to State) { <-b.release }
+func (b blocker) OnCallRejected()              { <-b.release }
+func (b blocker) OnCallCompleted(err error)    { <-b.release }
+
+func TestObserverTimeout(t *testing.T) {
+	cb := NewBreaker().TripAfter(1).ObserverTimeout(10 * time.Millisecond)
+	o := blocker{release: make(chan struct{})}
+	defer close(o.release)
+	cb.RegisterObserver(o)
+
+	start := time.Now()
+	err := cb.Protect(errorFunc)
+	if err == nil {
+		t.Fatalf("unexpected response: no error returned")
+	}
+
+	// the completed event times out and the state change event is
+	// dropped while the observer is still busy
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Fatalf("unexpected call duration: want less than %v, got %v", 500*time.Millisecond, d)
+	}
+
+	if cb.ObserverTimeouts() != 2 {
+		t.Fatalf("unexpected observer timeouts: want %d, got %d", 2, cb.ObserverTimeouts())
+	}
+}
+
+// logObserver logs breaker activity
+type logObserver struct{}
+
+func (logObserver) OnStateChange(from, to State) { log.Printf("%s -> %s", from, to) }
+func (logObserver) OnCallRejected()              { log.Println("rejected") }
+func (logObserver) OnCallCompleted(err error)    { log.Println("completed:", err) }
+
+func ExampleBreaker_RegisterObserver() {
+	cb := NewBreaker()
+	cb.RegisterObserver(logObserver{})
+
+	cb.Protect(func() error {
+		return nil
+	})
+}
+
+// resultRecorder records the results calls are counted as
+type resultRecorder struct {
+	recorder
+	results []Result
+}
+
+func (r *resultRecorder) OnCallCounted(res Result) {
+	r.results = append(r.results, res)
+}
+
+func TestResultObserver(t *testing.T) {
+	cb := NewBreaker().SlowCallThreshold(time.Millisecond)
+	r := &resultRecorder{}
+	cb.RegisterObserver(r)
+
+	cb.Protect(successFunc)
+	cb.Protect(errorFunc)
+	cb.Protect(func() error { return Degraded() })
+	cb.Protect(func() error {
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	})
+	cb.Protect(errorFunc, NoAccount())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cb.ProtectContext(ctx, func(context.Context) error {
+		cancel()
+		return ctx.Err()
+	})
+
+	want := []Result{ResultSuccess, ResultFailure, ResultDegraded, ResultFailure}
+	if slices.Equal(r.results, want) == false {
+		t.Fatalf("unexpected results: want %v, got %v", want, r.results)
+	}
+}
+
+func TestResults(t *testing.T) {
+	if ResultSuccess.String() != "success" {
+		t.Fatalf("unexpected result description: want %s, got %s", "success", ResultSuccess.String())
+	}
+
+	if ResultFailure.String() != "failure" {
+		t.Fatalf("unexpected result description: want %s, got %s", "failure", ResultFailure.String())
+	}
+
+	if ResultDegraded.String() != "degraded" {
+		t.Fatalf("unexpected result description: want %s, got %s", "degraded", ResultDegraded.String())
+	}
+
+	if Result(30).String() != "unknown" {
+		t.Fatalf("unexpected result description: want %s, got %s", "unknown", Result(30).String())
+	}
+}
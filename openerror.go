@@ -0,0 +1,88 @@
+package breaker
+
+import "time"
+
+// OpenError is the error returned when a call is rejected by the
+// breaker. It describes the breaker and how long callers should wait
+// before trying again. errors.Is(err, ErrOpen) reports true for an
+// *OpenError, as does errors.Is(err, ErrColdStart) for a call rejected
+// by ColdStart.
+type OpenError struct {
+	// Name is the name of the breaker, as given to Named or Get.
+	Name string
+
+	// State is the state of the breaker when the call was rejected. A
+	// call can be rejected while the breaker is partially open if the
+	// probe limit set by MaxProbes has been reached, or while it is
+	// closed if the rate limit set by ColdStart has been reached.
+	State State
+
+	// ColdStart is set if the call was rejected by the rate limit set by
+	// ColdStart.
+	ColdStart bool
+
+	// OpenedAt is the time the breaker last tripped.
+	OpenedAt time.Time
+
+	// RetryAfter is the time remaining until the breaker will next let a
+	// call through, as reported by Breaker.RetryAfter.
+	RetryAfter time.Duration
+
+	// description is the description registered for the state with
+	// Describe, if any.
+	description string
+}
+
+func (e *OpenError) Error() string {
+	switch {
+	case e.ColdStart && e.Name != "":
+		return "breaker " + e.Name + " cold starting"
+	case e.ColdStart:
+		return ErrColdStart.Error()
+	case e.description != "":
+		return e.description
+	case e.Name != "":
+		return "breaker " + e.Name + " open"
+	default:
+		return ErrOpen.Error()
+	}
+}
+
+// Unwrap returns ErrOpen.
+func (e *OpenError) Unwrap() error { return ErrOpen }
+
+// Is reports whether target is ErrColdStart and the call was rejected by
+// ColdStart.
+func (e *OpenError) Is(target error) bool {
+	return e.ColdStart && target == ErrColdStart
+}
+
+// Named sets the name of the breaker, which is reported in the errors
+// returned for rejected calls. Breakers created by a Registry are named
+// after the name they are registered under.
+func (b *Breaker) Named(name string) *Breaker {
+	return b.configure(func() {
+		b.name = name
+	})
+}
+
+// Name returns the name of the breaker, or an empty string if it has not
+// been named.
+func (b *Breaker) Name() string {
+	b.lock()
+	defer b.mu.Unlock()
+	return b.name
+}
+
+// openError returns the error for a rejected call, using the description
+// of the current state if one has been registered. It must be called
+// with the lock held.
+func (b *Breaker) openError() *OpenError {
+	return &OpenError{
+		Name:        b.name,
+		State:       b.state,
+		OpenedAt:    b.openedAt,
+		RetryAfter:  b.retryAfter(),
+		description: b.descriptions[b.state],
+	}
+}
@@ -0,0 +1,67 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOpenError(t *testing.T) {
+	cb := NewBreaker().Named("payments").TripAfter(1).ResetAfter(time.Minute)
+	before := time.Now()
+	cb.Protect(errorFunc)
+
+	err := cb.Protect(successFunc)
+	if !errors.Is(err, ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+
+	var oe *OpenError
+	if errors.As(err, &oe) == false {
+		t.Fatalf("unexpected error type: want *OpenError, got %T", err)
+	}
+	if oe.Name != "payments" {
+		t.Fatalf("unexpected name: want %q, got %q", "payments", oe.Name)
+	}
+	if oe.State != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, oe.State)
+	}
+	if oe.OpenedAt.Before(before) {
+		t.Fatalf("unexpected open time: want after %v, got %v", before, oe.OpenedAt)
+	}
+	if oe.RetryAfter <= 0 || oe.RetryAfter > time.Minute {
+		t.Fatalf("unexpected retry after: want up to %v, got %v", time.Minute, oe.RetryAfter)
+	}
+	if err.Error() != "breaker payments open" {
+		t.Fatalf("unexpected error message: want %q, got %q", "breaker payments open", err.Error())
+	}
+}
+
+func TestOpenErrorRegistry(t *testing.T) {
+	r := NewRegistry()
+	cb := r.Get("inventory")
+	if cb.Name() != "inventory" {
+		t.Fatalf("unexpected name: want %q, got %q", "inventory", cb.Name())
+	}
+
+	cb.Open()
+	var oe *OpenError
+	if err := cb.Protect(successFunc); errors.As(err, &oe) == false || oe.Name != "inventory" {
+		t.Fatalf("unexpected error: want *OpenError for %q, got %v", "inventory", err)
+	}
+}
+
+func TestOpenErrorColdStart(t *testing.T) {
+	err := &OpenError{Name: "search", State: StateOpen}
+	if errors.Is(err, ErrColdStart) {
+		t.Fatalf("unexpected error: want no match for %v", ErrColdStart)
+	}
+
+	err.ColdStart = true
+	if errors.Is(err, ErrColdStart) == false || errors.Is(err, ErrOpen) == false {
+		t.Fatalf("unexpected error: want match for %v and %v", ErrColdStart, ErrOpen)
+	}
+	if err.Error() != "breaker search cold starting" {
+		t.Fatalf("unexpected error message: want %q, got %q", "breaker search cold starting", err.Error())
+	}
+}
@@ -0,0 +1,53 @@
+package breaker
+
+// A CallOption changes how the breaker treats an individual call made
+// with Protect or ProtectContext.
+type CallOption func(*callOptions)
+
+// callOptions holds the options for a single call.
+type callOptions struct {
+	probe       bool
+	noAccount   bool
+	failureOnly bool
+}
+
+// newCallOptions applies opts to the default options.
+func newCallOptions(opts []CallOption) callOptions {
+	var o callOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// AsProbe makes the call as a probe of the protected system. It is let
+// through even if the breaker is open and the reset timeout has not yet
+// expired, or the maximum number of probes are already in flight. An
+// open breaker moves to the partially open state and the outcome of the
+// call is treated like that of any other probe. It is intended for
+// health checks that should be able to close the breaker promptly.
+func AsProbe() CallOption {
+	return func(o *callOptions) {
+		o.probe = true
+	}
+}
+
+// NoAccount keeps the outcome of the call out of the breaker's counters
+// and trip policy. The call is still rejected if the breaker is open,
+// and observers are still notified when it completes. It is intended
+// for administrative operations that say little about the health of the
+// protected system.
+func NoAccount() CallOption {
+	return func(o *callOptions) {
+		o.noAccount = true
+	}
+}
+
+// CountAsFailureOnly records the call if it fails but ignores it if it
+// succeeds, so that it can trip the breaker without inflating the
+// success counters.
+func CountAsFailureOnly() CallOption {
+	return func(o *callOptions) {
+		o.failureOnly = true
+	}
+}
@@ -0,0 +1,85 @@
+package breaker
+
+import (
+	"log"
+	"testing"
+	"time"
+)
+
+func TestAsProbe(t *testing.T) {
+	cb := NewBreaker().TripAfter(1).ResetAfter(time.Minute)
+	cb.Protect(errorFunc)
+
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+
+	// a probe is let through before the reset timeout expires
+	if err := cb.Protect(successFunc, AsProbe()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+
+	// a failed probe trips the breaker again
+	cb.Open()
+	cb.Protect(errorFunc, AsProbe())
+
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+}
+
+func TestNoAccount(t *testing.T) {
+	cb := NewBreaker().TripAfter(1)
+
+	cb.Protect(successFunc, NoAccount())
+	cb.Protect(errorFunc, NoAccount())
+
+	if cb.SuccessCount() != 0 {
+		t.Fatalf("unexpected success count: want %d, got %d", 0, cb.SuccessCount())
+	}
+
+	if cb.FailCount() != 0 {
+		t.Fatalf("unexpected fail count: want %d, got %d", 0, cb.FailCount())
+	}
+
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+}
+
+func TestCountAsFailureOnly(t *testing.T) {
+	cb := NewBreaker().TripAfter(1)
+
+	cb.Protect(successFunc, CountAsFailureOnly())
+
+	if cb.SuccessCount() != 0 {
+		t.Fatalf("unexpected success count: want %d, got %d", 0, cb.SuccessCount())
+	}
+
+	cb.Protect(errorFunc, CountAsFailureOnly())
+
+	if cb.FailCount() != 1 {
+		t.Fatalf("unexpected fail count: want %d, got %d", 1, cb.FailCount())
+	}
+
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+}
+
+func ExampleAsProbe() {
+	cb := NewBreaker()
+
+	err := cb.Protect(func() error {
+		// check the health of the protected system
+		return nil
+	}, AsProbe())
+
+	if err != nil {
+		log.Println(err)
+	}
+}
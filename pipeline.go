@@ -0,0 +1,58 @@
+package breaker
+
+import "fmt"
+
+// Pipeline is a sequence of stages, each calling a different external
+// system protected by its own breaker. Stages are run in order and the
+// pipeline stops at the first stage that fails or is rejected by an
+// open breaker.
+type Pipeline struct {
+	stages []stage
+}
+
+// stage is a single protected step in a pipeline
+type stage struct {
+	name string
+	b    *Breaker
+	f    func() error
+}
+
+// StageError is returned when a pipeline stops early. It identifies the
+// stage that failed and wraps the error returned by that stage.
+type StageError struct {
+	Stage string
+	Err   error
+}
+
+func (e *StageError) Error() string {
+	return fmt.Sprintf("stage %s: %v", e.Stage, e.Err)
+}
+
+// Unwrap returns the error returned by the failed stage.
+func (e *StageError) Unwrap() error {
+	return e.Err
+}
+
+// NewPipeline returns an empty pipeline.
+func NewPipeline() *Pipeline {
+	return &Pipeline{}
+}
+
+// Then appends a stage to the pipeline. The function f is protected by
+// the breaker b when the pipeline is run.
+func (p *Pipeline) Then(name string, b *Breaker, f func() error) *Pipeline {
+	p.stages = append(p.stages, stage{name: name, b: b, f: f})
+	return p
+}
+
+// Run calls each stage of the pipeline in order. If a stage returns an
+// error, or its breaker is open, the remaining stages are skipped and a
+// *StageError identifying the stage is returned.
+func (p *Pipeline) Run() error {
+	for _, s := range p.stages {
+		if err := s.b.Protect(s.f); err != nil {
+			return &StageError{Stage: s.name, Err: err}
+		}
+	}
+	return nil
+}
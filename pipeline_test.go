@@ -0,0 +1,89 @@
+package breaker
+
+import (
+	"errors"
+	"log"
+	"testing"
+)
+
+func TestPipeline(t *testing.T) {
+	var calls []string
+
+	err := NewPipeline().
+		Then("a", NewBreaker(), func() error {
+			calls = append(calls, "a")
+			return successFunc()
+		}).
+		Then("b", NewBreaker(), func() error {
+			calls = append(calls, "b")
+			return successFunc()
+		}).
+		Run()
+
+	if err != nil {
+		t.Fatalf("unexpected response: %v", err)
+	}
+
+	if len(calls) != 2 {
+		t.Fatalf("unexpected number of stages called: want %d, got %d", 2, len(calls))
+	}
+}
+
+func TestPipelineStageError(t *testing.T) {
+	cbB := NewBreaker()
+	called := false
+
+	err := NewPipeline().
+		Then("a", NewBreaker(), successFunc).
+		Then("b", cbB, errorFunc).
+		Then("c", NewBreaker(), func() error {
+			called = true
+			return successFunc()
+		}).
+		Run()
+
+	var se *StageError
+	if !errors.As(err, &se) {
+		t.Fatalf("unexpected error type: want %T, got %T", se, err)
+	}
+
+	if se.Stage != "b" {
+		t.Fatalf("unexpected stage: want %s, got %s", "b", se.Stage)
+	}
+
+	if called {
+		t.Fatalf("unexpected call to stage after failure")
+	}
+
+	if cbB.FailCount() != 1 {
+		t.Fatalf("unexpected fail count: want %d, got %d", 1, cbB.FailCount())
+	}
+}
+
+func TestPipelineOpenStage(t *testing.T) {
+	cbA := NewBreaker()
+	cbA.Open()
+
+	err := NewPipeline().Then("a", cbA, successFunc).Run()
+
+	if !errors.Is(err, ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+}
+
+func ExamplePipeline() {
+	err := NewPipeline().
+		Then("inventory", NewBreaker(), func() error {
+			// reserve stock
+			return nil
+		}).
+		Then("payments", NewBreaker(), func() error {
+			// take payment
+			return nil
+		}).
+		Run()
+
+	if err != nil {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,89 @@
+package breaker
+
+import "sync"
+
+// Pool maintains a breaker for each endpoint in a pool of resources,
+// such as the hosts behind a connection pool or the shards of a
+// partitioned backend. Breakers are created on
+// first use and share a configuration provided by the pool's
+// constructor function. The pool can filter the endpoints available for
+// checkout so that endpoints with an open breaker are skipped.
+type Pool struct {
+	mu       sync.Mutex
+	new      func() *Breaker
+	breakers map[string]*Breaker
+}
+
+// NewPool returns a new pool. The function f is called to create the
+// breaker for each endpoint and should return a newly configured
+// breaker on every call.
+//
+//	p := NewPool(func() *Breaker {
+//		return NewBreaker().TripAfter(3)
+//	})
+func NewPool(f func() *Breaker) *Pool {
+	return &Pool{
+		new:      f,
+		breakers: map[string]*Breaker{},
+	}
+}
+
+// Get returns the breaker for the endpoint identified by key, creating
+// it if necessary.
+func (p *Pool) Get(key string) *Breaker {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	b, ok := p.breakers[key]
+	if !ok {
+		b = p.new()
+		p.breakers[key] = b
+	}
+	return b
+}
+
+// Remove discards the breaker for the endpoint identified by key. It
+// should be called when an endpoint leaves the pool.
+func (p *Pool) Remove(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.breakers, key)
+}
+
+// Protect calls f protected by the breaker for the endpoint identified
+// by key.
+func (p *Pool) Protect(key string, f func() error) error {
+	return p.Get(key).Protect(f)
+}
+
+// Available returns the subset of keys whose breakers would currently
+// allow a call through, preserving their order. Endpoints without a
+// breaker are considered available. A pool can use this to exclude
+// failing endpoints when selecting a resource to check out.
+func (p *Pool) Available(keys []string) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var available []string
+	for _, k := range keys {
+		if b, ok := p.breakers[k]; ok && b.isReady() == false {
+			continue
+		}
+		available = append(available, k)
+	}
+	return available
+}
+
+// States returns the current state of the breaker for each endpoint in
+// the pool. It provides an aggregate view of a partially failing backend,
+// where some shards may be open while others remain closed.
+func (p *Pool) States() map[string]State {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	states := make(map[string]State, len(p.breakers))
+	for k, b := range p.breakers {
+		states[k] = b.CurrentState()
+	}
+	return states
+}
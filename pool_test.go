@@ -0,0 +1,83 @@
+package breaker
+
+import (
+	"fmt"
+	"log"
+	"testing"
+)
+
+func TestPool(t *testing.T) {
+	p := NewPool(func() *Breaker {
+		return NewBreaker().TripAfter(1)
+	})
+
+	if p.Get("a") != p.Get("a") {
+		t.Fatalf("unexpected breaker: want the same breaker for the same key")
+	}
+
+	p.Protect("a", errorFunc)
+	p.Protect("b", successFunc)
+
+	if p.Get("a").CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, p.Get("a").CurrentState())
+	}
+
+	if p.Get("b").CurrentState() != StateClosed {
+		t.Fatalf("unexpected state: want %v, got %v", StateClosed, p.Get("b").CurrentState())
+	}
+
+	available := p.Available([]string{"a", "b", "c"})
+	want := []string{"b", "c"}
+	if fmt.Sprint(available) != fmt.Sprint(want) {
+		t.Fatalf("unexpected available endpoints: want %v, got %v", want, available)
+	}
+
+	p.Remove("a")
+	available = p.Available([]string{"a", "b"})
+	if len(available) != 2 {
+		t.Fatalf("unexpected available endpoint count: want %d, got %d", 2, len(available))
+	}
+}
+
+func TestPoolStates(t *testing.T) {
+	p := NewPool(func() *Breaker {
+		return NewBreaker().TripAfter(1)
+	})
+
+	p.Protect("shard-1", errorFunc)
+	p.Protect("shard-2", successFunc)
+
+	states := p.States()
+	if len(states) != 2 {
+		t.Fatalf("unexpected number of states: want %d, got %d", 2, len(states))
+	}
+
+	if states["shard-1"] != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, states["shard-1"])
+	}
+
+	if states["shard-2"] != StateClosed {
+		t.Fatalf("unexpected state: want %v, got %v", StateClosed, states["shard-2"])
+	}
+}
+
+func ExamplePool() {
+	p := NewPool(func() *Breaker {
+		return NewBreaker().TripAfter(3)
+	})
+
+	hosts := p.Available([]string{"10.0.0.1:5432", "10.0.0.2:5432"})
+	if len(hosts) == 0 {
+		log.Println("no healthy hosts")
+		return
+	}
+
+	err := p.Protect(hosts[0], func() error {
+		// use a connection to the selected host
+		return nil
+	})
+
+	if err != nil {
+		log.Println(err)
+	}
+}
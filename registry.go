@@ -0,0 +1,101 @@
+package breaker
+
+import (
+	"container/list"
+	"sync"
+)
+
+// An Option configures a breaker created by a Registry.
+type Option func(b *Breaker)
+
+// Registry holds breakers by name so that different parts of an
+// application can share a breaker for each dependency. It is safe for
+// concurrent use.
+type Registry struct {
+	mu       sync.Mutex
+	breakers map[string]*list.Element
+	lru      *list.List
+	max      int
+}
+
+// registryEntry is a breaker held in a registry's LRU list.
+type registryEntry struct {
+	name string
+	b    *Breaker
+}
+
+// DefaultRegistry is the registry used by Get and All.
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry returns a new, empty registry.
+func NewRegistry() *Registry {
+	return NewLRURegistry(0)
+}
+
+// NewLRURegistry returns a new, empty registry holding at most max
+// breakers. When a new breaker would exceed the limit, the least
+// recently used breaker is evicted, so that a registry keyed by
+// arbitrary values such as tenant IDs or URLs does not grow without
+// limit. An evicted breaker continues to work for callers that hold
+// it, but a later Get for its name creates a new breaker. A max less
+// than one means no limit.
+func NewLRURegistry(max int) *Registry {
+	return &Registry{
+		breakers: map[string]*list.Element{},
+		lru:      list.New(),
+		max:      max,
+	}
+}
+
+// Get returns the breaker registered under name. If there is none, a new
+// breaker is created with the default configuration, the options are
+// applied to it in order, and it is registered. The options are ignored
+// if the breaker already exists.
+//
+//	cb := r.Get("payments", func(b *Breaker) {
+//		b.TripAfter(3).ResetAfter(time.Second)
+//	})
+func (r *Registry) Get(name string, opts ...Option) *Breaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if e, ok := r.breakers[name]; ok {
+		r.lru.MoveToFront(e)
+		return e.Value.(*registryEntry).b
+	}
+
+	b := NewBreaker().Named(name)
+	for _, opt := range opts {
+		opt(b)
+	}
+	r.breakers[name] = r.lru.PushFront(&registryEntry{name: name, b: b})
+
+	if r.max > 0 && r.lru.Len() > r.max {
+		oldest := r.lru.Remove(r.lru.Back()).(*registryEntry)
+		delete(r.breakers, oldest.name)
+	}
+	return b
+}
+
+// All returns a copy of the registry's breakers, keyed by name.
+func (r *Registry) All() map[string]*Breaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	all := make(map[string]*Breaker, len(r.breakers))
+	for name, e := range r.breakers {
+		all[name] = e.Value.(*registryEntry).b
+	}
+	return all
+}
+
+// Get returns the breaker registered under name in DefaultRegistry,
+// creating it with the given options if necessary.
+func Get(name string, opts ...Option) *Breaker {
+	return DefaultRegistry.Get(name, opts...)
+}
+
+// All returns the breakers registered in DefaultRegistry, keyed by name.
+func All() map[string]*Breaker {
+	return DefaultRegistry.All()
+}
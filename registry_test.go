@@ -0,0 +1,98 @@
+package breaker
+
+import (
+	"log"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+
+	b := r.Get("payments", func(b *Breaker) {
+		b.TripAfter(3)
+	})
+
+	if b.Config().TripAfter != 3 {
+		t.Fatalf("unexpected trip after: want %d, got %d", 3, b.Config().TripAfter)
+	}
+
+	// options are ignored once the breaker exists
+	if r.Get("payments", func(b *Breaker) { b.TripAfter(10) }) != b {
+		t.Fatalf("unexpected breaker: want the registered breaker")
+	}
+
+	if b.Config().TripAfter != 3 {
+		t.Fatalf("unexpected trip after: want %d, got %d", 3, b.Config().TripAfter)
+	}
+
+	r.Get("search")
+	all := r.All()
+	if len(all) != 2 || all["payments"] != b {
+		t.Fatalf("unexpected breakers: %v", all)
+	}
+}
+
+func TestRegistryConcurrent(t *testing.T) {
+	r := NewRegistry()
+	breakers := make([]*Breaker, 10)
+
+	var wg sync.WaitGroup
+	for i := range breakers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			breakers[i] = r.Get("shared")
+		}()
+	}
+	wg.Wait()
+
+	for _, b := range breakers {
+		if b != breakers[0] {
+			t.Fatalf("unexpected breaker: want all callers to share a breaker")
+		}
+	}
+}
+
+func ExampleGet() {
+	cb := Get("payments", func(b *Breaker) {
+		b.TripAfter(3).ResetAfter(time.Second)
+	})
+
+	err := cb.Protect(func() error {
+		// make the function call you are trying to protect
+		return nil
+	})
+	if err != nil {
+		log.Println(err)
+	}
+
+	for name, b := range All() {
+		log.Printf("%s: %v", name, b.CurrentState())
+	}
+}
+
+func TestLRURegistry(t *testing.T) {
+	r := NewLRURegistry(2)
+
+	a := r.Get("a")
+	r.Get("b")
+
+	// using a makes b the least recently used
+	r.Get("a")
+	r.Get("c")
+
+	all := r.All()
+	if len(all) != 2 {
+		t.Fatalf("unexpected registry size: want %d, got %d", 2, len(all))
+	}
+
+	if _, ok := all["b"]; ok {
+		t.Fatalf("unexpected breaker: want b to be evicted")
+	}
+
+	if r.Get("a") != a {
+		t.Fatalf("unexpected breaker: want a to be retained")
+	}
+}
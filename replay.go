@@ -0,0 +1,111 @@
+package breaker
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrParked is returned by ReplayQueue.Protect when a call rejected by
+// an open breaker has been parked for replay. It wraps ErrOpen.
+var ErrParked = fmt.Errorf("breaker: call parked for replay: %w", ErrOpen)
+
+// ReplayQueue parks calls rejected by an open breaker and replays them,
+// in order, when the breaker closes. It is intended for background work
+// that must eventually run but shouldn't hammer a dependency that is
+// down. Calls are only parked if there is room in the queue, and a
+// parked call may run long after it was made, so only idempotent work
+// that doesn't depend on the caller waiting should be protected by a
+// ReplayQueue.
+type ReplayQueue struct {
+	b         *Breaker
+	max       int
+	mu        sync.Mutex
+	parked    []func() error
+	replaying bool
+}
+
+// NewReplayQueue returns a queue parking up to max calls rejected by the
+// breaker b.
+func NewReplayQueue(b *Breaker, max int) *ReplayQueue {
+	q := &ReplayQueue{b: b, max: max}
+	b.RegisterObserver(replayObserver{q})
+	return q
+}
+
+// Protect calls f protected by the breaker. If the breaker rejects the
+// call and there is room in the queue, f is parked and ErrParked is
+// returned. If the queue is full, the error from the breaker is
+// returned and f is discarded.
+func (q *ReplayQueue) Protect(f func() error) error {
+	err := q.b.Protect(f)
+	if errors.Is(err, ErrOpen) == false {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.parked) >= q.max {
+		return err
+	}
+	q.parked = append(q.parked, f)
+	return ErrParked
+}
+
+// Len returns the number of parked calls.
+func (q *ReplayQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.parked)
+}
+
+// replay runs the parked calls until the queue is empty or the breaker
+// rejects a call, in which case the remaining calls stay parked.
+func (q *ReplayQueue) replay() {
+	q.mu.Lock()
+	if q.replaying {
+		q.mu.Unlock()
+		return
+	}
+	q.replaying = true
+	q.mu.Unlock()
+
+	defer func() {
+		q.mu.Lock()
+		q.replaying = false
+		q.mu.Unlock()
+	}()
+
+	for {
+		q.mu.Lock()
+		if len(q.parked) == 0 {
+			q.mu.Unlock()
+			return
+		}
+		f := q.parked[0]
+		q.mu.Unlock()
+
+		if errors.Is(q.b.Protect(f), ErrOpen) {
+			return
+		}
+
+		q.mu.Lock()
+		q.parked = q.parked[1:]
+		q.mu.Unlock()
+	}
+}
+
+// replayObserver starts a replay when the breaker closes.
+type replayObserver struct {
+	q *ReplayQueue
+}
+
+func (o replayObserver) OnStateChange(from, to State) {
+	if to == StateClosed {
+		go o.q.replay()
+	}
+}
+
+func (o replayObserver) OnCallRejected() {}
+
+func (o replayObserver) OnCallCompleted(err error) {}
@@ -0,0 +1,62 @@
+package breaker
+
+import (
+	"errors"
+	"log"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReplayQueue(t *testing.T) {
+	cb := NewBreaker()
+	q := NewReplayQueue(cb, 2)
+	cb.Open()
+
+	var runs atomic.Int64
+	f := func() error {
+		runs.Add(1)
+		return nil
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := q.Protect(f); !errors.Is(err, ErrParked) {
+			t.Fatalf("unexpected error: want %v, got %v", ErrParked, err)
+		}
+	}
+
+	// calls are discarded once the queue is full
+	err := q.Protect(f)
+	if !errors.Is(err, ErrOpen) || errors.Is(err, ErrParked) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+
+	if q.Len() != 2 {
+		t.Fatalf("unexpected queue length: want %d, got %d", 2, q.Len())
+	}
+
+	// parked calls are replayed once the breaker closes
+	cb.Close()
+	time.Sleep(20 * time.Millisecond)
+
+	if runs.Load() != 2 {
+		t.Fatalf("unexpected run count: want %d, got %d", 2, runs.Load())
+	}
+
+	if q.Len() != 0 {
+		t.Fatalf("unexpected queue length: want %d, got %d", 0, q.Len())
+	}
+}
+
+func ExampleReplayQueue() {
+	cb := NewBreaker()
+	q := NewReplayQueue(cb, 100)
+
+	err := q.Protect(func() error {
+		// send a notification that must eventually be delivered
+		return nil
+	})
+	if errors.Is(err, ErrParked) {
+		log.Println("notification will be sent when the breaker closes")
+	}
+}
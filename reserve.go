@@ -0,0 +1,100 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNoReservation is returned by Reservation.Protect once every reserved
+// call has been used or the reservation has been released.
+var ErrNoReservation = errors.New("breaker: no reserved calls left")
+
+// Reservation is a batch of calls admitted by the breaker in advance.
+type Reservation struct {
+	b         *Breaker
+	a         admission
+	bypass    bool
+	mu        sync.Mutex
+	remaining int
+}
+
+// Reserve asks the breaker to admit a batch of n calls at once, so that
+// a batched or pipelined producer can check admission once per batch
+// rather than once per item. Either the whole batch is admitted or
+// ErrOpen is returned and none of it is. While the breaker is partially
+// open, the batch is only admitted if there are n free probe slots.
+//
+// The calls in a reservation are admitted even if the breaker trips
+// before they are made, so that a batch is not left partially processed.
+// Their outcomes are counted as usual. Calls that are not needed should
+// be given back with Release. A reservation made while the breaker is
+// disabled passes its calls straight through and records nothing.
+func (b *Breaker) Reserve(n int) (*Reservation, error) {
+	if b.isDisabled() {
+		return &Reservation{b: b, bypass: true, remaining: n}, nil
+	}
+
+	a, err := b.admit(callOptions{}, n)
+	if err != nil {
+		return nil, err
+	}
+	return &Reservation{b: b, a: a, remaining: n}, nil
+}
+
+// Protect calls f using one of the reserved calls. It returns
+// ErrNoReservation if none are left.
+func (r *Reservation) Protect(f func() error) error {
+	return r.ProtectContext(context.Background(), func(context.Context) error {
+		return f()
+	})
+}
+
+// ProtectContext calls f with ctx using one of the reserved calls. It
+// returns ErrNoReservation if none are left. A recursive call made from
+// within a call protected by the same breaker is made directly, without
+// using a reserved call.
+func (r *Reservation) ProtectContext(ctx context.Context, f func(ctx context.Context) error) error {
+	if r.b.active(ctx) {
+		return f(ctx)
+	}
+
+	r.mu.Lock()
+	if r.remaining == 0 {
+		r.mu.Unlock()
+		return ErrNoReservation
+	}
+	r.remaining--
+	r.mu.Unlock()
+
+	if r.bypass {
+		return f(ctx)
+	}
+
+	start := time.Now()
+	err := call(r.b.mark(ctx), f)
+	return r.b.complete(ctx, callOptions{}, r.a, time.Since(start), err)
+}
+
+// Remaining returns the number of reserved calls that have not been used.
+func (r *Reservation) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.remaining
+}
+
+// Release gives back any reserved calls that have not been used, freeing
+// their probe slots if the breaker is partially open.
+func (r *Reservation) Release() {
+	r.mu.Lock()
+	n := r.remaining
+	r.remaining = 0
+	r.mu.Unlock()
+
+	if r.a.probe && n > 0 {
+		r.b.lock()
+		r.b.probes -= n
+		r.b.mu.Unlock()
+	}
+}
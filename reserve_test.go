@@ -0,0 +1,82 @@
+package breaker
+
+import (
+	"errors"
+	"log"
+	"testing"
+	"time"
+)
+
+func TestReserve(t *testing.T) {
+	cb := NewBreaker().TripAfter(1)
+
+	r, err := cb.Reserve(3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r.Protect(errorFunc)
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+
+	// reserved calls are made even though the breaker has tripped
+	if err := r.Protect(successFunc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r.Protect(successFunc)
+
+	if err := r.Protect(successFunc); !errors.Is(err, ErrNoReservation) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrNoReservation, err)
+	}
+
+	if _, err := cb.Reserve(1); !errors.Is(err, ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+}
+
+func TestReserveProbes(t *testing.T) {
+	cb := NewBreaker().TripAfter(1).ResetAfter(10 * time.Millisecond).MaxProbes(2)
+	cb.Protect(errorFunc)
+	time.Sleep(20 * time.Millisecond)
+
+	// a batch larger than the free probe slots is refused as a whole
+	if _, err := cb.Reserve(3); !errors.Is(err, ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+
+	r, err := cb.Reserve(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := cb.Reserve(1); !errors.Is(err, ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+
+	// released calls free their probe slots
+	r.Release()
+	if _, err := cb.Reserve(2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func ExampleBreaker_Reserve() {
+	cb := NewBreaker()
+	batch := []string{"a", "b", "c"}
+
+	r, err := cb.Reserve(len(batch))
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	defer r.Release()
+
+	for _, item := range batch {
+		r.Protect(func() error {
+			// process the item
+			log.Println(item)
+			return nil
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package breaker
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RequestScope snapshots the decisions of a set of breakers for the
+// duration of a single incoming request. The first call protected by a
+// breaker within the scope decides whether that breaker lets calls
+// through, and every later call in the scope reuses the decision. A
+// handler making several calls to the same dependency therefore sees a
+// consistent view, rather than having some calls succeed and others
+// rejected because the breaker changed state mid-request.
+//
+// The outcomes of calls that are let through are still recorded by the
+// breaker. A RequestScope should not outlive the request it was created
+// for.
+type RequestScope struct {
+	mu        sync.Mutex
+	decisions map[*Breaker]error
+}
+
+// NewRequestScope returns a new, empty scope.
+func NewRequestScope() *RequestScope {
+	return &RequestScope{decisions: map[*Breaker]error{}}
+}
+
+// Protect calls f protected by the breaker b, using the scope's decision
+// for b.
+func (s *RequestScope) Protect(b *Breaker, f func() error) error {
+	return s.ProtectContext(context.Background(), b, func(context.Context) error {
+		return f()
+	})
+}
+
+// ProtectContext calls f protected by the breaker b, using the scope's
+// decision for b. If b has not yet been used in the scope, the breaker
+// is asked whether the call may proceed and its answer is recorded as
+// the decision. If the decision was to reject calls, the error returned
+// by the breaker at the time is returned again and f is not called. As
+// with Breaker.ProtectContext, a recursive call on the same breaker is
+// made directly since it is accounted for by the outer call.
+func (s *RequestScope) ProtectContext(ctx context.Context, b *Breaker, f func(ctx context.Context) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if b.active(ctx) {
+		return f(ctx)
+	}
+
+	// a disabled breaker passes calls straight through without
+	// deciding for the scope
+	if b.isDisabled() {
+		return f(ctx)
+	}
+
+	s.mu.Lock()
+	err, decided := s.decisions[b]
+	var a admission
+	if decided == false {
+		a, err = b.admit(callOptions{}, 1)
+		s.decisions[b] = err
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	err = call(b.mark(ctx), f)
+	return b.complete(ctx, callOptions{}, a, time.Since(start), err)
+}
+
+// Allowed reports whether the scope lets calls protected by b through.
+// Breakers not yet used in the scope are reported as allowed along with
+// decided set to false.
+func (s *RequestScope) Allowed(b *Breaker) (allowed, decided bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err, decided := s.decisions[b]
+	return err == nil, decided
+}
@@ -0,0 +1,90 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"testing"
+)
+
+func TestRequestScope(t *testing.T) {
+	cb := NewBreaker().TripAfter(1)
+	scope := NewRequestScope()
+
+	if _, decided := scope.Allowed(cb); decided {
+		t.Fatalf("unexpected decision: want undecided")
+	}
+
+	if err := scope.Protect(cb, errorFunc); err == nil {
+		t.Fatalf("unexpected error: want error, got nil")
+	}
+
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+
+	// the breaker tripped but calls within the scope are still let through
+	if err := scope.Protect(cb, successFunc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if allowed, decided := scope.Allowed(cb); allowed == false || decided == false {
+		t.Fatalf("unexpected decision: want allowed, got allowed %v, decided %v", allowed, decided)
+	}
+
+	// a new scope sees the open breaker
+	scope = NewRequestScope()
+	if err := scope.Protect(cb, successFunc); !errors.Is(err, ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+
+	// and keeps rejecting calls once the breaker closes
+	cb.Reset()
+	if err := scope.Protect(cb, successFunc); !errors.Is(err, ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+}
+
+func TestRequestScopeNested(t *testing.T) {
+	cb := NewBreaker()
+	scope := NewRequestScope()
+
+	err := scope.ProtectContext(context.Background(), cb, func(ctx context.Context) error {
+		return scope.ProtectContext(ctx, cb, func(ctx context.Context) error {
+			return cb.ProtectContext(ctx, func(context.Context) error { return nil })
+		})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cb.SuccessCount() != 1 {
+		t.Fatalf("unexpected success count: want %d, got %d", 1, cb.SuccessCount())
+	}
+}
+
+func ExampleRequestScope() {
+	users := NewBreaker()
+	orders := NewBreaker()
+
+	// create a scope for each incoming request
+	scope := NewRequestScope()
+
+	err := scope.Protect(users, func() error {
+		// look up the user
+		return nil
+	})
+	if err != nil {
+		log.Println(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		err := scope.Protect(orders, func() error {
+			// fetch an order; all fetches see the same decision
+			return nil
+		})
+		if err != nil {
+			log.Println(err)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package breaker
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Priority is the importance of a unit of work to ShouldShed.
+type Priority int
+
+const (
+	// PriorityLow work is shed whenever the breaker would not admit
+	// every call.
+	PriorityLow Priority = -1
+
+	// PriorityNormal work is shed at random in proportion to the calls
+	// the breaker would reject.
+	PriorityNormal Priority = 0
+
+	// PriorityHigh work is shed only when the breaker would reject
+	// every call.
+	PriorityHigh Priority = 1
+)
+
+// AdmissionProbability returns the probability, between 0 and 1, that a
+// call made now would be admitted by the breaker. It is 1 while the
+// breaker is closed and 0 while it is open and not yet ready to reset.
+// While the breaker is partially open, it is the proportion of probe
+// slots that are free. While calls are limited by ColdStart, it is the
+// share of a call's worth of tokens available at the current rate.
+//
+// Frameworks can use it to shed work at the edge, before deserialising
+// or queueing a request that would only be rejected later.
+func (b *Breaker) AdmissionProbability() float64 {
+	b.lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case b.ready() == false:
+		return 0
+	case b.state == StatePartial && b.maxProbes > 0:
+		return float64(b.maxProbes-b.probes) / float64(b.maxProbes)
+	case b.state == StateClosed:
+		if _, tokens, ok := b.ramp(time.Now()); ok {
+			return min(tokens, 1)
+		}
+		return 1
+	default:
+		return 1
+	}
+}
+
+// ShouldShed reports whether work of the given priority should be shed
+// rather than started, based on AdmissionProbability.
+func (b *Breaker) ShouldShed(p Priority) bool {
+	a := b.AdmissionProbability()
+	switch {
+	case p < PriorityNormal:
+		return a < 1
+	case p > PriorityNormal:
+		return a == 0
+	default:
+		return rand.Float64() >= a
+	}
+}
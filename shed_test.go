@@ -0,0 +1,56 @@
+package breaker
+
+import (
+	"log"
+	"testing"
+	"time"
+)
+
+func TestAdmissionProbability(t *testing.T) {
+	cb := NewBreaker().TripAfter(1).ResetAfter(10 * time.Millisecond).MaxProbes(2)
+
+	if p := cb.AdmissionProbability(); p != 1 {
+		t.Fatalf("unexpected admission probability: want %v, got %v", 1.0, p)
+	}
+
+	cb.Protect(errorFunc)
+
+	if p := cb.AdmissionProbability(); p != 0 {
+		t.Fatalf("unexpected admission probability: want %v, got %v", 0.0, p)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	done, err := cb.Allow()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if p := cb.AdmissionProbability(); p != 0.5 {
+		t.Fatalf("unexpected admission probability: want %v, got %v", 0.5, p)
+	}
+	done(true)
+}
+
+func TestShouldShed(t *testing.T) {
+	cb := NewBreaker()
+
+	if cb.ShouldShed(PriorityLow) || cb.ShouldShed(PriorityNormal) || cb.ShouldShed(PriorityHigh) {
+		t.Fatalf("unexpected shedding: want no work shed while closed")
+	}
+
+	cb.Open()
+	if cb.ShouldShed(PriorityLow) == false || cb.ShouldShed(PriorityNormal) == false || cb.ShouldShed(PriorityHigh) == false {
+		t.Fatalf("unexpected shedding: want all work shed while open")
+	}
+}
+
+func ExampleBreaker_ShouldShed() {
+	cb := NewBreaker()
+
+	// decide whether to accept a request before reading its body
+	if cb.ShouldShed(PriorityNormal) {
+		log.Println("shedding request")
+		return
+	}
+}
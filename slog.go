@@ -0,0 +1,57 @@
+package breaker
+
+import (
+	"context"
+	"log/slog"
+)
+
+// slogObserver is an Observer that logs the activity of a breaker.
+type slogObserver struct {
+	name string
+	b    *Breaker
+	l    *slog.Logger
+}
+
+// NewSlogObserver returns an Observer that logs the activity of the
+// breaker b to l, or to slog.Default if l is nil. Every state change is
+// logged with the name of the breaker, the previous and new states and
+// the breaker's counters; a breaker opening is logged as a warning and
+// other changes as information. Completed and rejected calls are logged
+// at debug level.
+//
+//	cb.RegisterObserver(NewSlogObserver("payments", cb, logger))
+func NewSlogObserver(name string, b *Breaker, l *slog.Logger) Observer {
+	if l == nil {
+		l = slog.Default()
+	}
+	return &slogObserver{name: name, b: b, l: l}
+}
+
+func (o *slogObserver) OnStateChange(from, to State) {
+	level := slog.LevelInfo
+	if to == StateOpen {
+		level = slog.LevelWarn
+	}
+
+	o.l.LogAttrs(context.Background(), level, "breaker state changed",
+		slog.String("breaker", o.name),
+		slog.String("from", from.String()),
+		slog.String("to", to.String()),
+		slog.Int("failures", o.b.FailCount()),
+		slog.Int("successes", o.b.SuccessCount()),
+	)
+}
+
+func (o *slogObserver) OnCallRejected() {
+	o.l.LogAttrs(context.Background(), slog.LevelDebug, "breaker rejected call",
+		slog.String("breaker", o.name),
+	)
+}
+
+func (o *slogObserver) OnCallCompleted(err error) {
+	attrs := []slog.Attr{slog.String("breaker", o.name)}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	o.l.LogAttrs(context.Background(), slog.LevelDebug, "breaker call completed", attrs...)
+}
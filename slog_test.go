@@ -0,0 +1,43 @@
+package breaker
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestSlogObserver(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	cb := NewBreaker().TripAfter(2)
+	cb.RegisterObserver(NewSlogObserver("payments", cb, l))
+
+	// calls are logged at debug level and are not recorded by default
+	cb.Protect(errorFunc)
+	if buf.Len() != 0 {
+		t.Fatalf("unexpected log output: %s", buf.String())
+	}
+
+	cb.Protect(errorFunc)
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]any{
+		"level":    "WARN",
+		"msg":      "breaker state changed",
+		"breaker":  "payments",
+		"from":     "closed",
+		"to":       "open",
+		"failures": 2.0,
+	}
+	for k, v := range want {
+		if record[k] != v {
+			t.Fatalf("unexpected %s: want %v, got %v", k, v, record[k])
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package breaker
+
+import "time"
+
+// Stats is a snapshot of a breaker's counters and state. It can be
+// encoded as JSON for health endpoints and debug pages.
+type Stats struct {
+	// State is the state of the breaker.
+	State State `json:"state"`
+
+	// Failures and Successes are the counts of failed and successful
+	// calls since the counters were last reset.
+	Failures  int `json:"failures"`
+	Successes int `json:"successes"`
+
+	// Rejected is the number of calls rejected by the breaker since it
+	// was created.
+	Rejected int `json:"rejected"`
+
+	// LastFailure is the time of the most recent failure, or the zero
+	// time if no call has failed.
+	LastFailure time.Time `json:"last_failure"`
+
+	// LastStateChange is the time of the most recent state transition,
+	// or the zero time if the breaker has never changed state.
+	LastStateChange time.Time `json:"last_state_change"`
+
+	// TimeInState is the cumulative time the breaker has spent in each
+	// state since it was created, including the time spent so far in the
+	// current state.
+	TimeInState map[State]time.Duration `json:"time_in_state"`
+}
+
+// Stats returns a snapshot of the breaker's counters and state. Every
+// field is read at the same moment, so a dashboard polling Stats never
+// sees, for example, a fail count from before a trip alongside the open
+// state that followed it.
+func (b *Breaker) Stats() Stats {
+	b.lock()
+	defer b.mu.Unlock()
+
+	spent := map[State]time.Duration{}
+	for _, s := range []State{StateClosed, StatePartial, StateOpen} {
+		spent[s] = b.stateTime[s]
+	}
+	spent[b.state] += time.Since(b.enteredAt)
+
+	return Stats{
+		State:           b.state,
+		Failures:        b.failCount,
+		Successes:       b.successCount,
+		Rejected:        b.rejectedCount,
+		LastFailure:     b.lastFail,
+		LastStateChange: b.changedAt,
+		TimeInState:     spent,
+	}
+}
@@ -0,0 +1,82 @@
+package breaker
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	cb := NewBreaker().TripAfter(2)
+
+	s := cb.Stats()
+	if s.State != StateClosed || s.LastFailure.IsZero() == false || s.LastStateChange.IsZero() == false {
+		t.Fatalf("unexpected stats: got %+v", s)
+	}
+
+	before := time.Now()
+	cb.Protect(successFunc)
+	cb.Protect(errorFunc)
+	cb.Protect(errorFunc)
+	cb.Protect(successFunc)
+	cb.Protect(successFunc)
+
+	s = cb.Stats()
+	if s.State != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, s.State)
+	}
+	if s.Failures != 2 {
+		t.Fatalf("unexpected failures: want %v, got %v", 2, s.Failures)
+	}
+	if s.Successes != 1 {
+		t.Fatalf("unexpected successes: want %v, got %v", 1, s.Successes)
+	}
+	if s.Rejected != 2 {
+		t.Fatalf("unexpected rejected: want %v, got %v", 2, s.Rejected)
+	}
+	if s.LastFailure.Before(before) {
+		t.Fatalf("unexpected last failure: want after %v, got %v", before, s.LastFailure)
+	}
+	if s.LastStateChange.Before(s.LastFailure) {
+		t.Fatalf("unexpected last state change: want after %v, got %v", s.LastFailure, s.LastStateChange)
+	}
+}
+
+func TestStatsJSON(t *testing.T) {
+	cb := NewBreaker().TripAfter(1)
+	cb.Protect(errorFunc)
+	cb.Protect(successFunc)
+
+	b, err := json.Marshal(cb.Stats())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var m map[string]any
+	json.Unmarshal(b, &m)
+	if m["state"] != "open" {
+		t.Fatalf("unexpected state: want %q, got %v", "open", m["state"])
+	}
+	if m["failures"] != 1.0 || m["rejected"] != 1.0 {
+		t.Fatalf("unexpected counts: got %s", b)
+	}
+}
+
+func TestStatsTimeInState(t *testing.T) {
+	cb := NewBreaker().TripAfter(1).ResetAfter(time.Minute)
+
+	time.Sleep(20 * time.Millisecond)
+	cb.Protect(errorFunc)
+	time.Sleep(30 * time.Millisecond)
+
+	s := cb.Stats()
+	if s.TimeInState[StateClosed] < 20*time.Millisecond {
+		t.Fatalf("unexpected time closed: want at least %v, got %v", 20*time.Millisecond, s.TimeInState[StateClosed])
+	}
+	if s.TimeInState[StateOpen] < 30*time.Millisecond {
+		t.Fatalf("unexpected time open: want at least %v, got %v", 30*time.Millisecond, s.TimeInState[StateOpen])
+	}
+	if s.TimeInState[StatePartial] != 0 {
+		t.Fatalf("unexpected time partial: want %v, got %v", 0, s.TimeInState[StatePartial])
+	}
+}
@@ -0,0 +1,133 @@
+package breaker
+
+import (
+	"context"
+	"time"
+)
+
+// Transaction is an operation that touches several dependencies at
+// once, each protected by its own breaker. Every breaker is checked
+// before the operation starts, so that it fails fast if a dependency it
+// cannot do without is unavailable, and a failure is only counted
+// against the breaker of the dependency that caused it. This avoids
+// tripping the breaker of a healthy dependency because another one
+// failed during the same operation.
+type Transaction struct {
+	participants []participant
+	blame        func(err error) *Breaker
+}
+
+// participant is a breaker taking part in a transaction
+type participant struct {
+	b        *Breaker
+	critical bool
+}
+
+// NewTransaction returns an empty transaction. When the operation fails,
+// blame is called with its error and returns the breaker of the
+// dependency responsible, or nil if no dependency is to blame. If blame
+// is nil, a failure is counted against every breaker taking part.
+func NewTransaction(blame func(err error) *Breaker) *Transaction {
+	return &Transaction{blame: blame}
+}
+
+// With adds the breaker b to the transaction. If critical is true, the
+// operation is not run while b rejects calls. Otherwise the operation is
+// run without it and its outcome is not counted by b.
+func (t *Transaction) With(b *Breaker, critical bool) *Transaction {
+	t.participants = append(t.participants, participant{b: b, critical: critical})
+	return t
+}
+
+// Run asks each breaker in turn to admit the operation and, if every
+// critical breaker does, calls f. If a critical breaker rejects the
+// operation, its error is returned, f is not called and the calls
+// already admitted by other breakers are given back without being
+// counted. A disabled breaker always admits the operation and counts
+// nothing.
+//
+// If f succeeds, the success is counted by every breaker that admitted
+// it. If it fails, the failure is counted only by the breaker returned
+// by blame, and the other breakers count nothing. A panic in f is
+// recorded like any other failure and, unless every breaker counting it
+// recovers panics, raised again once all the breakers have been updated.
+func (t *Transaction) Run(ctx context.Context, f func(ctx context.Context) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	admitted := make([]participant, 0, len(t.participants))
+	tickets := make([]admission, 0, len(t.participants))
+	marked := ctx
+	for _, p := range t.participants {
+		// a breaker already protecting the caller accounts for the call
+		if p.b.active(ctx) {
+			continue
+		}
+
+		// a disabled breaker lets the operation through and counts
+		// nothing
+		if p.b.isDisabled() {
+			marked = p.b.mark(marked)
+			continue
+		}
+
+		a, err := p.b.admit(callOptions{}, 1)
+		if err == nil {
+			admitted = append(admitted, p)
+			tickets = append(tickets, a)
+			continue
+		}
+		if p.critical {
+			for i, a := range admitted {
+				a.b.release(tickets[i])
+			}
+			return err
+		}
+	}
+
+	for _, a := range admitted {
+		marked = a.b.mark(marked)
+	}
+
+	start := time.Now()
+	err := call(marked, f)
+	elapsed := time.Since(start)
+
+	var culprit *Breaker
+	if err != nil && t.blame != nil {
+		culprit = t.blame(err)
+	}
+
+	// every participant is completed or released before a panic from f
+	// is raised again, so that no probe slot is left behind
+	var raised any
+	panicked := false
+	for i, a := range admitted {
+		if err != nil && t.blame != nil && a.b != culprit {
+			a.b.release(tickets[i])
+			continue
+		}
+		func() {
+			defer func() {
+				if v := recover(); v != nil && panicked == false {
+					raised, panicked = v, true
+				}
+			}()
+			a.b.complete(ctx, callOptions{}, tickets[i], elapsed, err)
+		}()
+	}
+	if panicked {
+		panic(raised)
+	}
+	return err
+}
+
+// release gives back a call admitted by admit without counting it.
+func (b *Breaker) release(a admission) {
+	if a.probe {
+		b.lock()
+		b.probes--
+		b.mu.Unlock()
+	}
+}
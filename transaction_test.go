@@ -0,0 +1,149 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTransaction(t *testing.T) {
+	db := NewBreaker().TripAfter(1)
+	queue := NewBreaker().TripAfter(1)
+	errQueue := errors.New("queue unavailable")
+
+	tx := NewTransaction(func(err error) *Breaker {
+		if errors.Is(err, errQueue) {
+			return queue
+		}
+		return nil
+	}).With(db, true).With(queue, true)
+
+	if err := tx.Run(context.Background(), func(context.Context) error { return nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if db.SuccessCount() != 1 || queue.SuccessCount() != 1 {
+		t.Fatalf("unexpected success counts: want %d and %d, got %d and %d", 1, 1, db.SuccessCount(), queue.SuccessCount())
+	}
+
+	// a failure is only counted against the breaker to blame
+	err := tx.Run(context.Background(), func(context.Context) error { return errQueue })
+	if err != errQueue {
+		t.Fatalf("unexpected error: want %v, got %v", errQueue, err)
+	}
+	if db.CurrentState() != StateClosed {
+		t.Fatalf("unexpected state: want %v, got %v", StateClosed, db.CurrentState())
+	}
+	if queue.CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, queue.CurrentState())
+	}
+
+	// the operation is not run while a critical breaker is open
+	called := false
+	err = tx.Run(context.Background(), func(context.Context) error {
+		called = true
+		return nil
+	})
+	if !errors.Is(err, ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+	if called {
+		t.Fatalf("unexpected call: want operation not run")
+	}
+	if db.SuccessCount() != 1 {
+		t.Fatalf("unexpected success count: want %d, got %d", 1, db.SuccessCount())
+	}
+}
+
+func TestTransactionNonCritical(t *testing.T) {
+	db := NewBreaker().TripAfter(1)
+	cache := NewBreaker().TripAfter(1)
+	cache.Open()
+
+	tx := NewTransaction(nil).With(db, true).With(cache, false)
+
+	called := false
+	err := tx.Run(context.Background(), func(context.Context) error {
+		called = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if called == false {
+		t.Fatalf("unexpected call: want operation run")
+	}
+	if db.SuccessCount() != 1 || cache.SuccessCount() != 0 {
+		t.Fatalf("unexpected success counts: want %d and %d, got %d and %d", 1, 0, db.SuccessCount(), cache.SuccessCount())
+	}
+}
+
+func TestTransactionDisabled(t *testing.T) {
+	db := NewBreaker().TripAfter(1)
+	db.Protect(errorFunc)
+	db.Disable()
+	queue := NewBreaker()
+
+	tx := NewTransaction(nil).With(db, true).With(queue, true)
+	err := tx.Run(context.Background(), func(ctx context.Context) error {
+		return db.ProtectContext(ctx, func(context.Context) error { return nil })
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if db.SuccessCount() != 0 || queue.SuccessCount() != 1 {
+		t.Fatalf("unexpected success counts: want %d and %d, got %d and %d", 0, 1, db.SuccessCount(), queue.SuccessCount())
+	}
+	if db.CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, db.CurrentState())
+	}
+}
+
+func TestTransactionReleasesProbes(t *testing.T) {
+	db := NewBreaker().TripAfter(1).ResetAfter(time.Millisecond).MaxProbes(1)
+	queue := NewBreaker()
+	db.Protect(errorFunc)
+	time.Sleep(5 * time.Millisecond)
+	queue.Open()
+
+	tx := NewTransaction(nil).With(db, true).With(queue, true)
+	if err := tx.Run(context.Background(), func(context.Context) error { return nil }); !errors.Is(err, ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+
+	// the probe admitted by db is given back
+	if err := db.Protect(successFunc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestTransactionPanic(t *testing.T) {
+	db := NewBreaker().TripAfter(1).ResetAfter(time.Millisecond).MaxProbes(1)
+	queue := NewBreaker().TripAfter(1).ResetAfter(time.Millisecond).MaxProbes(1)
+	db.Open()
+	queue.Open()
+	time.Sleep(5 * time.Millisecond)
+
+	tx := NewTransaction(nil).With(db, true).With(queue, true)
+	func() {
+		defer func() {
+			if v := recover(); v != "boom" {
+				t.Fatalf("unexpected panic: want %v, got %v", "boom", v)
+			}
+		}()
+		tx.Run(context.Background(), func(context.Context) error {
+			panic("boom")
+		})
+	}()
+
+	// both breakers counted the failure and gave back their probe slots
+	for _, cb := range []*Breaker{db, queue} {
+		if cb.CurrentState() != StateOpen {
+			t.Fatalf("unexpected state: want %v, got %v", StateOpen, cb.CurrentState())
+		}
+		time.Sleep(5 * time.Millisecond)
+		if err := cb.Protect(successFunc); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
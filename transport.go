@@ -0,0 +1,43 @@
+package breaker
+
+import (
+	"context"
+	"net/http"
+)
+
+// Transport is an http.RoundTripper protected by a breaker. Transport
+// errors and responses with a 5xx status code are counted as failures.
+// While the breaker is open, requests fail with ErrOpen without being
+// sent.
+type Transport struct {
+	base http.RoundTripper
+	b    *Breaker
+}
+
+// NewTransport returns a Transport that sends requests using base,
+// protected by the breaker b. If base is nil, http.DefaultTransport is
+// used.
+func NewTransport(base http.RoundTripper, b *Breaker) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{base: base, b: b}
+}
+
+// RoundTrip implements http.RoundTripper. A 5xx response is returned to
+// the caller unchanged, with a nil error, after being recorded as a
+// failure. As required of a RoundTripper, the request body is closed
+// even if the request is not sent.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	sent := false
+	resp, err := ProtectResult(req.Context(), t.b, func(ctx context.Context) (*http.Response, error) {
+		sent = true
+		return t.base.RoundTrip(req.WithContext(ctx))
+	}, func(resp *http.Response, err error) bool {
+		return err != nil || resp.StatusCode >= 500
+	})
+	if sent == false && req.Body != nil {
+		req.Body.Close()
+	}
+	return resp, err
+}
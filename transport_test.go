@@ -0,0 +1,132 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestTransport(t *testing.T) {
+	var status atomic.Int64
+	status.Store(http.StatusOK)
+
+	var requests atomic.Int64
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer ts.Close()
+
+	cb := NewBreaker().TripAfter(2)
+	client := &http.Client{Transport: NewTransport(nil, cb)}
+
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if cb.SuccessCount() != 1 {
+		t.Fatalf("unexpected success count: want %d, got %d", 1, cb.SuccessCount())
+	}
+
+	// server errors are returned to the caller and counted as failures
+	status.Store(http.StatusServiceUnavailable)
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(ts.URL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("unexpected status: want %d, got %d", http.StatusServiceUnavailable, resp.StatusCode)
+		}
+	}
+
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+
+	// requests are not sent while the breaker is open
+	if _, err := client.Get(ts.URL); !errors.Is(err, ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+
+	if requests.Load() != 3 {
+		t.Fatalf("unexpected request count: want %d, got %d", 3, requests.Load())
+	}
+}
+
+func TestTransportError(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	ts.Close()
+
+	cb := NewBreaker()
+	client := &http.Client{Transport: NewTransport(nil, cb)}
+
+	if _, err := client.Get(ts.URL); err == nil {
+		t.Fatalf("unexpected error: want error, got nil")
+	}
+
+	if cb.FailCount() != 1 {
+		t.Fatalf("unexpected fail count: want %d, got %d", 1, cb.FailCount())
+	}
+}
+
+// closeBody records whether a request body was closed
+type closeBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *closeBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestTransportClosesBody(t *testing.T) {
+	cb := NewBreaker().TripAfter(1)
+	cb.Protect(errorFunc)
+	tr := NewTransport(nil, cb)
+
+	body := &closeBody{Reader: strings.NewReader("payload")}
+	req := httptest.NewRequest(http.MethodPost, "http://example.com", body)
+	if _, err := tr.RoundTrip(req); !errors.Is(err, ErrOpen) {
+		t.Fatalf("unexpected error: want %v, got %v", ErrOpen, err)
+	}
+	if body.closed == false {
+		t.Fatalf("unexpected body: want closed after rejection")
+	}
+
+	cb.Reset()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	body = &closeBody{Reader: strings.NewReader("payload")}
+	req = httptest.NewRequest(http.MethodPost, "http://example.com", body).WithContext(ctx)
+	if _, err := tr.RoundTrip(req); !errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected error: want %v, got %v", context.Canceled, err)
+	}
+	if body.closed == false {
+		t.Fatalf("unexpected body: want closed after cancellation")
+	}
+}
+
+func ExampleNewTransport() {
+	cb := NewBreaker()
+	client := &http.Client{Transport: NewTransport(http.DefaultTransport, cb)}
+
+	resp, err := client.Get("https://example.com")
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	defer resp.Body.Close()
+	log.Println(resp.Status)
+}
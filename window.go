@@ -0,0 +1,39 @@
+package breaker
+
+// SlidingWindow configures the breaker to count only the outcomes of the
+// last n transactions. The fail and success counters, and any trip policy
+// that uses them, then reflect recent behaviour rather than every
+// transaction since the breaker last closed. Configuring the window
+// returns the counters to zero. A value of n less than one disables the
+// window.
+func (b *Breaker) SlidingWindow(n int) *Breaker {
+	return b.configure(func() {
+		b.window = nil
+		if n > 0 {
+			b.window = make([]bool, n)
+		}
+		b.resetCounters()
+	})
+}
+
+// record adds the outcome of a transaction to the sliding window,
+// removing the oldest outcome from the counters once the window is full.
+// It must be called before the counters are incremented.
+func (b *Breaker) record(success bool) {
+	if len(b.window) == 0 {
+		return
+	}
+
+	if b.windowLen == len(b.window) {
+		if b.window[b.windowPos] {
+			b.successCount--
+		} else {
+			b.failCount--
+		}
+	} else {
+		b.windowLen++
+	}
+
+	b.window[b.windowPos] = success
+	b.windowPos = (b.windowPos + 1) % len(b.window)
+}
@@ -0,0 +1,56 @@
+package breaker
+
+import "testing"
+
+func TestSlidingWindow(t *testing.T) {
+	cb := NewBreaker().TripAfter(3).SlidingWindow(4)
+
+	outcomes := []bool{false, false, true, true, true, false}
+
+	for _, o := range outcomes {
+		cb.Protect(func() error {
+			if o {
+				return successFunc()
+			}
+			return errorFunc()
+		})
+	}
+
+	// only the last four outcomes are counted
+	if cb.FailCount() != 1 {
+		t.Fatalf("unexpected fail count: want %d, got %d", 1, cb.FailCount())
+	}
+
+	if cb.SuccessCount() != 3 {
+		t.Fatalf("unexpected success count: want %d, got %d", 3, cb.SuccessCount())
+	}
+
+	if cb.CurrentState() != StateClosed {
+		t.Fatalf("unexpected state: want %v, got %v", StateClosed, cb.CurrentState())
+	}
+
+	cb.Protect(errorFunc)
+	cb.Protect(errorFunc)
+
+	if cb.CurrentState() != StateOpen {
+		t.Fatalf("unexpected final state: want %v, got %v", StateOpen, cb.CurrentState())
+	}
+}
+
+func TestSlidingWindowReset(t *testing.T) {
+	cb := NewBreaker().SlidingWindow(2)
+
+	cb.Protect(errorFunc)
+	cb.Reset()
+	cb.Protect(successFunc)
+	cb.Protect(successFunc)
+	cb.Protect(successFunc)
+
+	if cb.FailCount() != 0 {
+		t.Fatalf("unexpected fail count: want %d, got %d", 0, cb.FailCount())
+	}
+
+	if cb.SuccessCount() != 2 {
+		t.Fatalf("unexpected success count: want %d, got %d", 2, cb.SuccessCount())
+	}
+}